@@ -1,19 +1,37 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 	"qask_telegram/internal/app/bot"
 )
 
+var (
+	configPath string
+)
+
+func init() {
+	flag.StringVar(&configPath, "config-path", "configs/bot.toml", "path to config file")
+}
+
 func main() {
-	token := os.Getenv("TG_BOT_TOKEN")
+	flag.Parse()
+
+	config, err := bot.LoadConfig(configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if token := os.Getenv("TG_BOT_TOKEN"); token != "" {
+		config.Token = token
+	}
 
-	if token == "" {
+	if config.Token == "" {
 		log.Fatal("Token not found")
 	}
 
-	if err := bot.Start(token); err != nil {
+	if err := bot.Start(config); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -3,7 +3,9 @@ module qask_telegram
 go 1.13
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/go-telegram-bot-api/telegram-bot-api v4.6.4+incompatible
+	github.com/prometheus/client_golang v1.5.1
 	github.com/sirupsen/logrus v1.5.0
 	github.com/stretchr/objx v0.2.0 // indirect
 	github.com/technoweenie/multipartstreamer v1.0.1 // indirect
@@ -0,0 +1,64 @@
+package antiflood
+
+import "fmt"
+
+//DefaultClass is a class of commands not listed in Config.Commands
+const DefaultClass = "default"
+
+//ClassConfig is a limit of one command class
+type ClassConfig struct {
+	Burst  int     `toml:"burst"`
+	Refill float64 `toml:"refill"`
+}
+
+//Config ...
+type Config struct {
+	Classes     map[string]ClassConfig `toml:"classes"`
+	Commands    map[string]string      `toml:"commands"`
+	MuteAfter   int                    `toml:"mute_after"`
+	MuteSeconds int                    `toml:"mute_seconds"`
+}
+
+//NewConfig returns the default anti-flood config
+func NewConfig() *Config {
+	return &Config{
+		Classes: map[string]ClassConfig{
+			DefaultClass: {Burst: 10, Refill: 1},
+			"question":   {Burst: 5, Refill: 0.2},
+		},
+		Commands: map[string]string{
+			"/getQuestion":    "question",
+			"/getMathProblem": "question",
+			"/register":       "question",
+		},
+		MuteAfter:   20,
+		MuteSeconds: 300,
+	}
+}
+
+//Validate checks that the default class is set and every class can refill its bucket
+func (c *Config) Validate() error {
+	if _, ok := c.Classes[DefaultClass]; !ok {
+		return fmt.Errorf("antiflood class \"%s\" is not set", DefaultClass)
+	}
+
+	for name, class := range c.Classes {
+		if class.Burst < 1 {
+			return fmt.Errorf("antiflood class \"%s\": burst must be at least 1", name)
+		}
+
+		if class.Refill <= 0 {
+			return fmt.Errorf("antiflood class \"%s\": refill must be positive", name)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) class(command string) string {
+	if class, ok := c.Commands[command]; ok {
+		return class
+	}
+
+	return DefaultClass
+}
@@ -0,0 +1,141 @@
+package antiflood
+
+import (
+	"qask_telegram/internal/app/ratelimit"
+	"sync"
+	"time"
+)
+
+const cleanupTimeout = 10 * time.Minute
+
+//Verdict is a result of an update check
+type Verdict int
+
+const (
+	//Allowed means the update may be handled
+	Allowed Verdict = iota
+	//Limited means the user exceeded the limit of the command class
+	Limited
+	//Muted means the user has just been muted for flooding
+	Muted
+	//StillMuted means the user is muted
+	StillMuted
+)
+
+type userState struct {
+	buckets    map[string]*ratelimit.Bucket
+	violations int
+	mutedUntil time.Time
+}
+
+//Limiter limits incoming updates per user
+type Limiter struct {
+	mu     sync.Mutex
+	config *Config
+	users  map[int]*userState
+}
+
+//New creates a limiter
+func New(config *Config) *Limiter {
+	l := &Limiter{
+		config: config,
+		users:  make(map[int]*userState),
+	}
+
+	go l.cleanup()
+
+	return l
+}
+
+//Check takes a token of the command class from the user's bucket
+func (l *Limiter) Check(userID int, command string) Verdict {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.users[userID]
+	if !ok {
+		state = &userState{
+			buckets: make(map[string]*ratelimit.Bucket),
+		}
+		l.users[userID] = state
+	}
+
+	now := time.Now()
+	if now.Before(state.mutedUntil) {
+		return StillMuted
+	}
+
+	class := l.config.class(command)
+	bucket, ok := state.buckets[class]
+	if !ok {
+		classConfig, ok := l.config.Classes[class]
+		if !ok {
+			classConfig = l.config.Classes[DefaultClass]
+		}
+		bucket = ratelimit.NewBucket(classConfig.Burst, classConfig.Refill)
+		state.buckets[class] = bucket
+	}
+
+	if bucket.Allow() {
+		state.violations = 0
+		return Allowed
+	}
+
+	state.violations++
+	if l.config.MuteAfter > 0 && state.violations >= l.config.MuteAfter {
+		state.violations = 0
+		state.mutedUntil = now.Add(l.muteDuration())
+		return Muted
+	}
+
+	return Limited
+}
+
+//MuteDuration returns how long flooding users are muted
+func (l *Limiter) MuteDuration() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.muteDuration()
+}
+
+func (l *Limiter) muteDuration() time.Duration {
+	return time.Duration(l.config.MuteSeconds) * time.Second
+}
+
+//SetConfig replaces the limits, users' buckets are reset but mutes are kept
+func (l *Limiter) SetConfig(config *Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.config = config
+	for _, state := range l.users {
+		state.buckets = make(map[string]*ratelimit.Bucket)
+		state.violations = 0
+	}
+}
+
+func (l *Limiter) cleanup() {
+	for range time.Tick(cleanupTimeout) {
+		l.mu.Lock()
+		now := time.Now()
+		for userID, state := range l.users {
+			if now.Before(state.mutedUntil) {
+				continue
+			}
+
+			idle := true
+			for _, bucket := range state.buckets {
+				if !bucket.Full() {
+					idle = false
+					break
+				}
+			}
+
+			if idle {
+				delete(l.users, userID)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,83 @@
+package antiflood
+
+import "testing"
+
+func testConfig() *Config {
+	return &Config{
+		Classes: map[string]ClassConfig{
+			DefaultClass: {Burst: 2, Refill: 0.001},
+			"question":   {Burst: 1, Refill: 0.001},
+		},
+		Commands: map[string]string{
+			"/getQuestion": "question",
+		},
+		MuteAfter:   3,
+		MuteSeconds: 60,
+	}
+}
+
+func TestLimiterClasses(t *testing.T) {
+	l := New(testConfig())
+
+	if v := l.Check(1, "/getQuestion"); v != Allowed {
+		t.Errorf("first question: got %d, want Allowed", v)
+	}
+	if v := l.Check(1, "/getQuestion"); v != Limited {
+		t.Errorf("second question: got %d, want Limited", v)
+	}
+
+	// other commands and users have their own buckets
+	if v := l.Check(1, "/settings"); v != Allowed {
+		t.Errorf("default class: got %d, want Allowed", v)
+	}
+	if v := l.Check(2, "/getQuestion"); v != Allowed {
+		t.Errorf("other user: got %d, want Allowed", v)
+	}
+}
+
+func TestLimiterMute(t *testing.T) {
+	l := New(testConfig())
+
+	l.Check(1, "/getQuestion")
+	want := []Verdict{Limited, Limited, Muted, StillMuted}
+	for i, w := range want {
+		if v := l.Check(1, "/getQuestion"); v != w {
+			t.Errorf("check %d: got %d, want %d", i+1, v, w)
+		}
+	}
+
+	// a mute applies to all commands
+	if v := l.Check(1, "/settings"); v != StillMuted {
+		t.Errorf("muted user: got %d, want StillMuted", v)
+	}
+
+	// mutes are kept when the config is reloaded
+	l.SetConfig(testConfig())
+	if v := l.Check(1, "/settings"); v != StillMuted {
+		t.Errorf("muted user after reload: got %d, want StillMuted", v)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := NewConfig().Validate(); err != nil {
+		t.Errorf("default config: %s", err)
+	}
+
+	config := testConfig()
+	config.Classes["question"] = ClassConfig{Burst: 5, Refill: 0}
+	if config.Validate() == nil {
+		t.Error("a class without refill is accepted")
+	}
+
+	config = testConfig()
+	config.Classes["question"] = ClassConfig{Burst: 0, Refill: 1}
+	if config.Validate() == nil {
+		t.Error("a class without burst is accepted")
+	}
+
+	config = testConfig()
+	delete(config.Classes, DefaultClass)
+	if config.Validate() == nil {
+		t.Error("a config without the default class is accepted")
+	}
+}
@@ -0,0 +1,318 @@
+package bot
+
+import (
+	"fmt"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const (
+	adminRecentLimit = 10
+	adminTimeFormat  = "2006-01-02 15:04"
+	//maxMessageRunes is the telegram limit of a message text
+	maxMessageRunes = 4096
+	//maxReportRunes shortens texts of reports and of their questions in the list of reports
+	maxReportRunes = 500
+)
+
+type adminList struct {
+	mu  sync.RWMutex
+	ids map[int]bool
+}
+
+func newAdminList(ids []int) *adminList {
+	a := &adminList{}
+	a.set(ids)
+
+	return a
+}
+
+func (a *adminList) set(ids []int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.ids = make(map[int]bool, len(ids))
+	for _, id := range ids {
+		a.ids[id] = true
+	}
+}
+
+func (a *adminList) all() []int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ids := make([]int, 0, len(a.ids))
+	for id := range a.ids {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (a *adminList) contains(id int) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.ids[id]
+}
+
+//adminOnly is a middleware hiding admin commands from other users
+func (h *messageHandler) adminOnly(next router.RouterHandler) router.RouterHandler {
+	return func(user *model.User, u *tgbotapi.Update) {
+		if !h.admins.contains(u.Message.From.ID) {
+			h.logger.Warnf("User \"%d\" tried to use admin command \"%s\"", u.Message.From.ID, u.Message.Text)
+			h.unavailableCommand(u.Message.Chat.ID, user.Lang())
+			return
+		}
+
+		next(user, u)
+	}
+}
+
+func (h *messageHandler) configureAdminRouter() {
+	h.logger.Debugf("Configuring admin commands router ...")
+	h.adminRouter.NewRoute("users", true, h.handleAdminUsers())
+	h.adminRouter.NewRoute("user", true, h.handleAdminUser())
+	h.adminRouter.NewRoute("ban", true, h.handleAdminBan(true))
+	h.adminRouter.NewRoute("unban", true, h.handleAdminBan(false))
+	h.adminRouter.NewRoute("reports", true, h.handleAdminReports())
+	h.adminRouter.NewRoute("submissions", true, h.handleAdminSubmissions())
+	h.adminRouter.NewRoute("reload", true, h.handleAdminReload())
+	h.logger.Debugf("Configuring admin commands router done")
+}
+
+func (h *messageHandler) handleAdmin() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Admin'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.Message.Text)
+		if handler := h.adminRouter.GetHandler(strings.Join(args, " ")); handler != nil {
+			handler(user, u)
+			return
+		}
+
+		h.sendText(u.Message.Chat.ID, user.Tr("help.admin"))
+	}
+}
+
+//adminArgs returns arguments of an admin subcommand, e.g. ["42"] for "/admin user 42"
+func adminArgs(u *tgbotapi.Update) []string {
+	_, args := router.ParseCommand(u.Message.Text)
+	if len(args) < 2 {
+		return nil
+	}
+
+	return args[1:]
+}
+
+//adminTarget finds the user passed as the first argument of an admin subcommand
+func (h *messageHandler) adminTarget(user *model.User, u *tgbotapi.Update) *model.User {
+	args := adminArgs(u)
+	if len(args) != 1 {
+		h.sendText(u.Message.Chat.ID, user.Tr("admin.user_id_required"))
+		return nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		h.sendText(u.Message.Chat.ID, user.Tr("admin.user_id_required"))
+		return nil
+	}
+
+	target := h.store.User().FindUser(id)
+	if target == nil {
+		h.sendText(u.Message.Chat.ID, user.Tr("admin.user_not_found", id))
+		return nil
+	}
+
+	return target
+}
+
+func (h *messageHandler) handleAdminUsers() router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'AdminUsers'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		users := h.store.User().All()
+
+		var registered, active, banned, inactive int
+		recent := make([]*model.User, 0)
+		for _, usr := range users {
+			if usr.Registered {
+				registered++
+				recent = append(recent, usr)
+			}
+			if time.Since(usr.LastSeen) < activeUserTimeout {
+				active++
+			}
+			if usr.Banned {
+				banned++
+			}
+			if usr.Inactive {
+				inactive++
+			}
+		}
+
+		sort.Slice(recent, func(i, j int) bool {
+			return recent[i].RegisteredAt.After(recent[j].RegisteredAt)
+		})
+		if len(recent) > adminRecentLimit {
+			recent = recent[:adminRecentLimit]
+		}
+
+		var b strings.Builder
+		b.WriteString(user.Tr("admin.users", len(users), registered, active, banned, inactive))
+		b.WriteString("\n\n")
+		b.WriteString(user.Tr("admin.recent_registrations"))
+		for _, usr := range recent {
+			fmt.Fprintf(&b, "\n%d %s (@%s) %s", usr.UserId, usr.FirstName, usr.UserName, usr.RegisteredAt.Format(adminTimeFormat))
+		}
+
+		h.sendText(u.Message.Chat.ID, b.String())
+	}
+}
+
+func (h *messageHandler) handleAdminUser() router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'AdminUser'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		target := h.adminTarget(user, u)
+		if target == nil {
+			return
+		}
+
+		question := "-"
+		if target.Question != nil {
+			question = target.Question.Question
+		}
+
+		text := user.Tr("admin.user_info",
+			target.UserId,
+			target.FirstName,
+			target.UserName,
+			target.Lang(),
+			target.Registered,
+			target.Banned,
+			target.Inactive,
+			target.QuestSubscribtion,
+			target.MathProblemSubscribtion,
+			target.CreatedAt.Format(adminTimeFormat),
+			target.LastSeen.Format(adminTimeFormat),
+			question)
+
+		h.sendText(u.Message.Chat.ID, text)
+	}
+}
+
+func (h *messageHandler) handleAdminBan(ban bool) router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'AdminBan' (ban=%t)", ban)
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		target := h.adminTarget(user, u)
+		if target == nil {
+			return
+		}
+
+		if ban && h.admins.contains(target.UserId) {
+			h.sendText(u.Message.Chat.ID, user.Tr("admin.cannot_ban_admin"))
+			return
+		}
+
+		target.Banned = ban
+
+		if ban {
+			// The countdown and the game of a banned user must not write to the user any more
+			target.AnswerQuestion()
+			target.Session = nil
+
+			h.logger.Infof("User \"%d\" banned by admin \"%d\"", target.UserId, u.Message.From.ID)
+			h.sendText(u.Message.Chat.ID, user.Tr("admin.banned", target.UserId))
+		} else {
+			h.logger.Infof("User \"%d\" unbanned by admin \"%d\"", target.UserId, u.Message.From.ID)
+			h.sendText(u.Message.Chat.ID, user.Tr("admin.unbanned", target.UserId))
+		}
+	}
+}
+
+func (h *messageHandler) handleAdminReports() router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'AdminReports'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		reports := h.store.Report().Recent(adminRecentLimit)
+		if len(reports) == 0 {
+			h.sendText(u.Message.Chat.ID, user.Tr("admin.no_reports"))
+			return
+		}
+
+		entries := make([]string, 0, len(reports))
+		for _, report := range reports {
+			entry := fmt.Sprintf("#%d %d %s\n%s", report.ID, report.UserID, report.CreatedAt.Format(adminTimeFormat), shortText(report.Text, maxReportRunes))
+			if report.Question != nil {
+				entry += "\n" + user.Tr("admin.report_question", shortText(report.Question.Question, maxReportRunes))
+			}
+			entries = append(entries, entry)
+		}
+
+		h.sendEntries(u.Message.Chat.ID, entries)
+	}
+}
+
+func (h *messageHandler) handleAdminReload() router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'AdminReload'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if err := h.reload(); err != nil {
+			h.logger.Errorf("Can not reload config: %s", err)
+			h.sendText(u.Message.Chat.ID, user.Tr("error.internal", err))
+			return
+		}
+
+		h.sendText(u.Message.Chat.ID, user.Tr("admin.reloaded"))
+	}
+}
+
+func (h *messageHandler) sendText(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	h.sender.Send(msg)
+}
+
+//sendEntries sends the entries separated by empty lines in as few messages as the limit of a message allows,
+//an entry must fit into a message
+func (h *messageHandler) sendEntries(chatID int64, entries []string) {
+	var b strings.Builder
+	length := 0
+	for _, entry := range entries {
+		entryLength := utf8.RuneCountInString(entry)
+		if length > 0 && length+2+entryLength > maxMessageRunes {
+			h.sendText(chatID, b.String())
+			b.Reset()
+			length = 0
+		}
+
+		if length > 0 {
+			b.WriteString("\n\n")
+			length += 2
+		}
+		b.WriteString(entry)
+		length += entryLength
+	}
+
+	h.sendText(chatID, b.String())
+}
+
+//shortText cuts the text to max runes
+func shortText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	return string(runes[:max-1]) + "…"
+}
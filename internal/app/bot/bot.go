@@ -1,8 +1,19 @@
 package bot
 
 import (
+	"qask_telegram/internal/app/antiflood"
+	"qask_telegram/internal/app/broadcast"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/qask"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/source"
 	"qask_telegram/internal/app/store"
 	"qask_telegram/internal/app/store/cache"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/sirupsen/logrus"
@@ -15,23 +26,34 @@ type updateHandler interface {
 }
 
 type tgbot struct {
+	config               *Config
 	bot                  *tgbotapi.BotAPI
+	sender               *sender.Sender
+	qask                 *qask.Client
+	questions            *source.Prefetcher
+	inlineQuestions      *source.Prefetcher
 	logger               *logrus.Logger
-	updChan              *tgbotapi.UpdatesChannel
+	updChan              *updatesChannel
+	lastPoll             int64
 	store                store.Store
+	antiFlood            *antiflood.Limiter
+	admins               *adminList
+	broadcaster          *broadcast.Broadcaster
 	callBackQueryHandler *callBackQueryHandler
 	messageHandler       *messageHandler
+	groupHandler         *groupHandler
+	inlineHandler        *inlineHandler
 }
 
 //Start ...
-func Start(token string) error {
-	bot, err := startBot(token)
+func Start(config *Config) error {
+	bot, err := startBot(config.Token)
 	if err != nil {
 		return err
 	}
 
 	logger := logrus.New()
-	level, err := logrus.ParseLevel("debug")
+	level, err := logrus.ParseLevel(config.LogLevel)
 	if err != nil {
 		return err
 	}
@@ -40,19 +62,59 @@ func Start(token string) error {
 
 	st := cache.New(logger)
 
+	bot.config = config
 	bot.logger = logger
 	bot.store = st
+	bot.antiFlood = antiflood.New(config.AntiFlood)
+	bot.admins = newAdminList(config.Admins)
+	bot.sender = sender.New(bot.bot, logger)
+	bot.sender.OnBlocked(bot.handleBlocked)
+	bot.qask = qask.New(config.QaskURL, logger)
+	questions, err := configuredSource(config, bot.qask, logger)
+	if err != nil {
+		return err
+	}
+	bot.questions = source.NewPrefetcher(questions, config.Prefetch, logger)
+	// Random inline questions are prefetched per user apart from the questions of the user's chat
+	bot.inlineQuestions = source.NewPrefetcher(questions, config.Prefetch, logger)
+	bot.broadcaster = broadcast.New(bot.sender, st, logger)
+
+	bot.callBackQueryHandler = newCallBackQueryHandler(bot)
+	bot.messageHandler = newMessageHandler(bot)
+	bot.groupHandler = newGroupHandler(bot)
+	bot.inlineHandler = newInlineHandler(bot)
 
-	bot.callBackQueryHandler = newCallBackQueryHandler(bot.bot, logger, st)
-	bot.messageHandler = newMessageHandler(bot.bot, logger, st)
+	bot.registerMetrics()
+	bot.startHTTPServer()
+
+	updatesChan := bot.pollUpdates()
+	bot.updChan = &updatesChan
+
+	for upd := range *bot.updChan {
+		metrics.UpdatesReceived.WithLabelValues(updateType(&upd)).Inc()
+
+		if upd.PollAnswer != nil {
+			go bot.handlePollAnswer(upd.PollAnswer)
+			continue
+		}
 
-	for update := range *bot.updChan {
-		if update.CallbackQuery == nil && update.Message == nil && update.ChannelPost == nil {
+		update := upd.Update
+		if isInlineUpdate(&update) {
+			go bot.ServeUpdate(&update, bot.inlineHandler)
+		} else if update.CallbackQuery == nil && update.Message == nil && update.ChannelPost == nil {
 			continue
 		} else if update.CallbackQuery != nil {
-			go bot.ServeUpdate(&update, bot.callBackQueryHandler)
+			if update.CallbackQuery.Message != nil && isGroupChat(update.CallbackQuery.Message.Chat) {
+				go bot.ServeUpdate(&update, bot.groupHandler)
+			} else {
+				go bot.ServeUpdate(&update, bot.callBackQueryHandler)
+			}
 		} else if update.Message != nil {
-			go bot.ServeUpdate(&update, bot.messageHandler)
+			if isGroupChat(update.Message.Chat) {
+				go bot.ServeUpdate(&update, bot.groupHandler)
+			} else {
+				go bot.ServeUpdate(&update, bot.messageHandler)
+			}
 		}
 	}
 	return nil
@@ -64,22 +126,18 @@ func startBot(token string) (*tgbot, error) {
 		return nil, err
 	}
 
-	uc := tgbotapi.NewUpdate(0)
-	uc.Timeout = 60
-
-	updatesChan, err := bot.GetUpdatesChan(uc)
-	if err != nil {
-		return nil, err
-	}
-
 	return &tgbot{
 		bot:     bot,
 		logger:  nil,
-		updChan: &updatesChan,
+		updChan: nil,
 	}, nil
 }
 
 func (b *tgbot) ServeUpdate(update *tgbotapi.Update, handler updateHandler) {
+	if !b.allowUpdate(update) {
+		return
+	}
+
 	if handler.updateIsCommand(update) {
 		handler.handleCommand(update)
 	} else {
@@ -113,6 +171,122 @@ func (b *tgbot) ServeUpdate(update *tgbotapi.Update, handler updateHandler) {
 	*/
 }
 
+//pushTo queues a message to the user unless the user has blocked the bot
+func pushTo(s *sender.Sender, user *model.User, c tgbotapi.Chattable) {
+	if user.Inactive {
+		return
+	}
+
+	s.Push(c)
+}
+
+//handleBlocked marks a user who has blocked the bot inactive,
+//inactive users are excluded from broadcasts and pushes until they write to the bot again
+func (b *tgbot) handleBlocked(chatID int64) {
+	user := b.store.User().FindUser(int(chatID))
+	if user == nil || user.Inactive {
+		return
+	}
+
+	user.Inactive = true
+	b.logger.Infof("User \"%d\" has blocked the bot and is marked inactive", user.UserId)
+}
+
+//reloadConfig applies settings that can be changed without restart: log level, admins and anti-flood limits
+func (b *tgbot) reloadConfig() error {
+	config, err := LoadConfig(b.config.path)
+	if err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(config.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	b.logger.SetLevel(level)
+	b.admins.set(config.Admins)
+	b.antiFlood.SetConfig(config.AntiFlood)
+
+	b.logger.Infof("Config \"%s\" reloaded", b.config.path)
+	return nil
+}
+
+//allowUpdate checks the sender's anti-flood limits. Commands, callbacks and answers are limited,
+//other messages in groups are talk between members and are not counted
+func (b *tgbot) allowUpdate(update *tgbotapi.Update) bool {
+	var from *tgbotapi.User
+	var text string
+	private := false
+
+	if update.CallbackQuery != nil {
+		from = update.CallbackQuery.From
+		text = update.CallbackQuery.Data
+		private = update.CallbackQuery.Message != nil && update.CallbackQuery.Message.Chat.IsPrivate()
+	} else if update.Message != nil {
+		from = update.Message.From
+		text = update.Message.Text
+		private = update.Message.Chat.IsPrivate()
+
+		if !private && !strings.HasPrefix(text, "/") && !b.groupQuestionOpen(update.Message.Chat.ID) {
+			return true
+		}
+	} else if update.InlineQuery != nil {
+		from = update.InlineQuery.From
+	}
+
+	if from == nil {
+		return true
+	}
+
+	user := b.store.User().FindUser(from.ID)
+	if user != nil && user.Banned {
+		b.logger.Debugf("Update from banned user \"%d\" ignored", from.ID)
+		return false
+	}
+
+	if b.admins.contains(from.ID) {
+		return true
+	}
+
+	command := ""
+	if strings.HasPrefix(text, "/") {
+		command, _ = router.ParseCommand(text)
+		command, _ = router.SplitBotName(command)
+	}
+
+	switch b.antiFlood.Check(from.ID, command) {
+	case antiflood.Allowed:
+		return true
+	case antiflood.Muted:
+		b.logger.Warnf("User \"%d\" is muted for flooding", from.ID)
+
+		// A group member who has never written to the bot is muted silently
+		if user == nil && !private {
+			break
+		}
+
+		lang := locale.Normalize(from.LanguageCode)
+		if user != nil {
+			lang = user.Lang()
+		}
+
+		minutes := int((b.antiFlood.MuteDuration() + time.Minute - 1) / time.Minute)
+		text := locale.Get(lang, "antiflood.muted", locale.Plural(lang, "minutes", minutes, minutes))
+		b.sender.Send(tgbotapi.NewMessage(int64(from.ID), text))
+	default:
+		b.logger.Debugf("Update from user \"%d\" rejected by anti-flood: command=\"%s\"", from.ID, command)
+	}
+
+	return false
+}
+
+//groupQuestionOpen reports whether messages in the group chat may be answers to its question
+func (b *tgbot) groupQuestionOpen(chatID int64) bool {
+	group := b.store.Group().FindGroup(chatID)
+	return group != nil && group.Open()
+}
+
 /*
 
 func (b *tgbot) SendError(chatId int64, errString string) {
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/broadcast"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func broadcastFilterLabel(user *model.User, filter broadcast.Filter) string {
+	if lang, ok := filter.Language(); ok {
+		return user.Tr("broadcast.filter.language", user.Tr("language."+lang))
+	}
+
+	return user.Tr("broadcast.filter." + string(filter))
+}
+
+//broadcastDraftMessage is a text and a keyboard to choose recipients and start or cancel the broadcast
+func broadcastDraftMessage(user *model.User, br *broadcast.Broadcast, recipients int) (string, tgbotapi.InlineKeyboardMarkup) {
+	text := user.Tr("broadcast.draft", broadcastFilterLabel(user, br.Filter), recipients)
+
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+	var row []tgbotapi.InlineKeyboardButton
+	for _, filter := range broadcast.Filters() {
+		label := broadcastFilterLabel(user, filter)
+		if filter == br.Filter {
+			label = "✅ " + label
+		}
+
+		row = append(row, makeButton("/broadcastTarget "+string(filter), label))
+		if len(row) == 2 {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(row...))
+			row = nil
+		}
+	}
+	if len(row) != 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(row...))
+	}
+
+	btnSend := makeButton("/broadcastSend", user.Tr("broadcast.send"))
+	btnCancel := makeButton("/broadcastCancel", user.Tr("broadcast.cancel"))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnSend, btnCancel))
+
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (h *messageHandler) handleBroadcast() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Broadcast'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		user.OnText = func(text string) {
+			br, err := h.broadcaster.Draft(user.UserId, text)
+			if err != nil {
+				h.sendText(user.UserID(), user.Tr("broadcast.already_running"))
+				return
+			}
+
+			h.sendText(user.UserID(), br.Text)
+
+			draftText, markup := broadcastDraftMessage(user, br, len(h.broadcaster.Recipients(br.Filter)))
+			msg := tgbotapi.NewMessage(user.UserID(), draftText)
+			msg.ReplyMarkup = markup
+			h.sender.Send(msg)
+		}
+
+		h.sendText(user.UserID(), user.Tr("broadcast.enter_text"))
+	}
+}
+
+//adminOnly is a middleware hiding admin callbacks from other users
+func (h *callBackQueryHandler) adminOnly(next router.RouterHandler) router.RouterHandler {
+	return func(user *model.User, u *tgbotapi.Update) {
+		if !h.admins.contains(u.CallbackQuery.From.ID) {
+			h.logger.Warnf("User \"%d\" tried to use admin callback \"%s\"", u.CallbackQuery.From.ID, u.CallbackQuery.Data)
+			h.unavailableCommand(user)
+			return
+		}
+
+		next(user, u)
+	}
+}
+
+func (h *callBackQueryHandler) handleBroadcastTarget() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'BroadcastTarget'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 || !broadcast.Filter(args[0]).IsValid() {
+			h.unavailableCommand(user)
+			return
+		}
+
+		br, err := h.broadcaster.SetFilter(user.UserId, broadcast.Filter(args[0]))
+		if err != nil {
+			h.internalError(user, err)
+			return
+		}
+
+		text, markup := broadcastDraftMessage(user, br, len(h.broadcaster.Recipients(br.Filter)))
+		msg := tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, text)
+		msg.ReplyMarkup = &markup
+		h.sender.Send(msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleBroadcastSend() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'BroadcastSend'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		messageID := u.CallbackQuery.Message.MessageID
+
+		err := h.broadcaster.Start(user.UserId, func(result broadcast.Result) {
+			key := "broadcast.done"
+			if result.Cancelled {
+				key = "broadcast.cancelled"
+			}
+
+			text := user.Tr(key, result.Total, result.Delivered, result.Blocked, result.Failed)
+			h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), messageID, text))
+		})
+		if err != nil {
+			h.internalError(user, err)
+			return
+		}
+
+		markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/broadcastCancel", user.Tr("broadcast.stop"))))
+		msg := tgbotapi.NewEditMessageText(user.UserID(), messageID, user.Tr("broadcast.running"))
+		msg.ReplyMarkup = &markup
+		h.sender.Send(msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleBroadcastCancel() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'BroadcastCancel'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		running, err := h.broadcaster.Cancel(user.UserId)
+		if err != nil {
+			h.internalError(user, err)
+			return
+		}
+
+		// The report of a running broadcast replaces the message when it stops
+		if running {
+			return
+		}
+
+		h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, user.Tr("broadcast.discarded")))
+	}
+}
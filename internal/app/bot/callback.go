@@ -1,34 +1,49 @@
 package bot
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
-	"io/ioutil"
-	"net/http"
+	"qask_telegram/internal/app/broadcast"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
 	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/qask"
 	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/source"
 	"qask_telegram/internal/app/store"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/sirupsen/logrus"
 )
 
 type callBackQueryHandler struct {
-	bot    *tgbotapi.BotAPI
-	logger *logrus.Logger
-	router *router.Router
-	store  store.Store
+	bot         *tgbotapi.BotAPI
+	sender      *sender.Sender
+	qask        *qask.Client
+	questions   *source.Prefetcher
+	admins      *adminList
+	broadcaster *broadcast.Broadcaster
+	categories  []string
+	logger      *logrus.Logger
+	router      *router.Router
+	store       store.Store
 }
 
-func newCallBackQueryHandler(bot *tgbotapi.BotAPI, logger *logrus.Logger, store store.Store) *callBackQueryHandler {
+func newCallBackQueryHandler(b *tgbot) *callBackQueryHandler {
 	cH := &callBackQueryHandler{
-		bot:    bot,
-		logger: logger,
-		router: router.NewRouter(logger),
-		store:  store,
+		bot:         b.bot,
+		sender:      b.sender,
+		qask:        b.qask,
+		questions:   b.questions,
+		admins:      b.admins,
+		broadcaster: b.broadcaster,
+		categories:  b.config.Categories,
+		logger:      b.logger,
+		router:      router.NewRouter("callback", b.bot.Self.UserName, b.logger),
+		store:       b.store,
 	}
 
 	cH.configureRouter()
@@ -43,14 +58,50 @@ func (h *callBackQueryHandler) configureRouter() {
 	h.router.NewRoute("/profile", false, h.handleProfile())
 	h.router.NewRoute("/getQuestion", false, h.handleGetQuestion())
 	h.router.NewRoute("/showAnswer", false, h.handleShowAnswer())
+	h.router.NewRoute("/hint", false, h.handleHint())
+	h.router.NewRoute("/startSession", false, h.handleStartSession())
+	h.router.NewRoute("/skipQuestion", false, h.handleSkipQuestion())
+	h.router.NewRoute("/pauseSession", false, h.handlePauseSession())
+	h.router.NewRoute("/resumeSession", false, h.handleResumeSession())
+	h.router.NewRoute("/sendReport", false, h.handleSendReport())
 	h.router.NewRoute("/showQuestion", false, h.handleShowQuestion())
 	h.router.NewRoute("/showComment", false, h.handleShowComment())
 	h.router.NewRoute("/getMathProblem", false, h.handleGetMathProblem())
 	h.router.NewRoute("/settings", false, h.handleSettings())
 	h.router.NewRoute("/subscribtions", false, h.handleSubscriptions())
+	h.router.NewRoute("/timer", false, h.handleTimer())
+	h.router.NewRoute("/setTimer", false, h.handleSetTimer())
+	h.router.NewRoute("/categories", false, h.handleCategories())
+	h.router.NewRoute("/toggleCategory", false, h.handleToggleCategory())
+	h.router.NewRoute("/difficulty", false, h.handleDifficulty())
+	h.router.NewRoute("/setDifficulty", false, h.handleSetDifficulty())
+	h.router.NewRoute("/resetHistory", false, h.handleResetHistory())
+	h.router.NewRoute("/favorite", false, h.handleFavorite())
+	h.router.NewRoute("/favoritesPage", false, h.handleFavoritesPage())
+	h.router.NewRoute("/review", false, h.handleReview())
+	h.router.NewRoute("/grade", false, h.handleGrade())
 	h.router.NewRoute("/back", false, h.handleBack())
 	h.router.NewRoute("/setFirstName", false, h.handleSetFirstName())
 	h.router.NewRoute("/setUserName", false, h.handleSetUserName())
+	h.router.NewRoute("/language", false, h.handleLanguage())
+	h.router.NewRoute("/setLanguage", false, h.handleSetLanguage())
+	h.router.NewRoute("/broadcastTarget", false, h.handleBroadcastTarget(), h.adminOnly)
+	h.router.NewRoute("/broadcastSend", false, h.handleBroadcastSend(), h.adminOnly)
+	h.router.NewRoute("/broadcastCancel", false, h.handleBroadcastCancel(), h.adminOnly)
+	h.router.NewRoute("/team", false, h.handleTeam())
+	h.router.NewRoute("/createTeam", false, h.handleCreateTeam())
+	h.router.NewRoute("/joinTeam", false, h.handleJoinTeam())
+	h.router.NewRoute("/leaveTeam", false, h.handleLeaveTeam())
+	h.router.NewRoute("/teamQuestion", false, h.handleTeamQuestion())
+	h.router.NewRoute("/teamAnswer", false, h.handleTeamAnswer())
+	h.router.NewRoute("/teamShowAnswer", false, h.handleTeamShowAnswer())
+	h.router.NewRoute("/teamLeaderboard", false, h.handleTeamLeaderboard())
+	h.router.NewRoute("/submitSend", false, h.handleSubmitSend())
+	h.router.NewRoute("/submitCancel", false, h.handleSubmitCancel())
+	h.router.NewRoute("/approveSubmission", false, h.handleApproveSubmission(), h.adminOnly)
+	h.router.NewRoute("/rejectSubmission", false, h.handleRejectSubmission(), h.adminOnly)
+	h.router.NewRoute("/editSubmission", false, h.handleEditSubmission(), h.adminOnly)
+	h.router.NewRoute("/editSubmissionField", false, h.handleEditSubmissionField(), h.adminOnly)
 	h.logger.Debugf("Configuring callback commands router done")
 }
 
@@ -62,12 +113,21 @@ func (h *callBackQueryHandler) handleCommand(u *tgbotapi.Update) {
 	h.logger.Infof("Received CallBack Command: command=\"%s\" chatId=\"%d\"", u.CallbackQuery.Data, u.CallbackQuery.Message.Chat.ID)
 
 	chatID := u.CallbackQuery.Message.Chat.ID
-	user := h.store.User().FindUser(int(chatID))
+	user := h.store.User().FindUser(u.CallbackQuery.From.ID)
+	// Users are kept in memory, so after a restart the buttons of old messages are pressed by unknown users
+	if user == nil {
+		lang := locale.Normalize(u.CallbackQuery.From.LanguageCode)
+		h.sender.Send(tgbotapi.NewMessage(chatID, locale.Get(lang, "error.unknown_user")))
+		return
+	}
+	user.Seen(u.CallbackQuery.From)
+
 	if handler := h.router.GetHandler(u.CallbackQuery.Data); handler != nil {
 		handler(user, u)
 	} else {
-		errMsg := tgbotapi.NewMessage(chatID, "Ошибка! Неизвестная команда")
-		h.bot.Send(errMsg)
+		lang := locale.Normalize(u.CallbackQuery.From.LanguageCode)
+		errMsg := tgbotapi.NewMessage(chatID, locale.Get(lang, "error.unknown_command"))
+		h.sender.Send(errMsg)
 	}
 }
 
@@ -78,61 +138,37 @@ func (h *callBackQueryHandler) updateIsCommand(u *tgbotapi.Update) bool {
 func (h *callBackQueryHandler) handleRegisterUser() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'RegisterUser'")
 
-	type request struct {
-		FirstName string `json:"firstName"`
-		UserName  string `json:"userName"`
-		TgID      int64  `json:"tgId"`
-		From      string `json:"from"`
-	}
-
 	return func(user *model.User, u *tgbotapi.Update) {
 		if user.WelcomeMessage.MessageID != u.CallbackQuery.Message.MessageID {
 			return
 		}
 
 		// User registration
-		req := &request{}
-		req.FirstName = user.FirstName
-		req.UserName = user.UserName
-		req.TgID = user.UserID()
-		req.From = "telegram"
-
-		b, _ := json.Marshal(req)
-
-		resp, err := http.Post("http://172.20.0.3:30001/users", "application/json", bytes.NewReader(b))
-		if err != nil {
-			h.internalError(user.UserID(), err)
-			return
-		}
-
-		if resp.StatusCode != http.StatusCreated {
-			// Error while register
-			b, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				// Internal telegram bot error
-				h.internalError(user.UserID(), err)
+		if err := h.qask.RegisterUser(context.Background(), user); err != nil {
+			h.logger.Errorf("Can not register user \"%d\": %s", user.UserID(), err)
+			if qask.IsUnavailable(err) {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("error.service_unavailable")))
 				return
 			}
 
-			h.internalError(user.UserID(), errors.New(string(b)))
+			h.internalError(user, err)
 			return
 		}
 
 		user.Registered = true
+		user.RegisteredAt = time.Now()
 
 		message := model.WelcomeMessageAfterRegister(user)
 		user.WelcomeMessageHead = message
-		h.bot.Send(message.Msg)
+		h.sender.Send(message.Msg)
 
-		registeredHelpText :=
-			`Вам доступны следующие команды:
-/play - играть
-/profile - настройки профиля
-/newpass - сгенерировать новый пароль
-`
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("help.registered"))
+		h.sender.Send(msg)
 
-		msg := tgbotapi.NewMessage(user.UserID(), registeredHelpText)
-		h.bot.Send(msg)
+		if q := user.SharedQuestion; q != nil {
+			user.SharedQuestion = nil
+			h.askSharedQuestion(user, q)
+		}
 	}
 }
 
@@ -144,9 +180,9 @@ func (h *callBackQueryHandler) handleProfile() router.RouterHandler {
 			return
 		}
 
-		message := model.ProfileMain(user)
+		message := profileMessage(h.store, user)
 		user.ProfileMessageHead = message
-		user.ProfileMessage, _ = h.bot.Send(message.Msg)
+		user.ProfileMessage, _ = h.sender.Send(message.Msg)
 	}
 }
 
@@ -154,104 +190,183 @@ func (h *callBackQueryHandler) handleGetQuestion() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'GetQuestion'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
-		question := model.GetQuestion(user)
-		if question == nil {
-			return
-		}
-
-		user.Question = question
+		h.sendQuestion(user)
+	}
+}
 
-		msg := tgbotapi.NewMessage(user.UserID(), user.Question.Question)
+//sendQuestion gets a new question for the user, in a session it becomes the next question of the session
+func (h *callBackQueryHandler) sendQuestion(user *model.User) {
+	question, err := nextQuestion(context.Background(), h.questions, h.store, user.UserID(), user.QuestionFilter())
+	if err != nil {
+		h.logger.Errorf("Can not get question for user \"%d\": %s", user.UserID(), err)
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr(questionErrorKey(err))))
+		return
+	}
 
-		var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+	if user.InSession() {
+		user.Session.NextQuestion(question)
+	}
+	user.HistoryEntry = h.store.History().AddQuestion(user.UserId, question)
+	user.ReviewCard = nil
+	metrics.QuestionsServed.Inc()
 
-		btnAnswer := makeButton("/showAnswer", "Показать ответ")
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAnswer))
+	h.askQuestion(user, question)
+}
 
-		btnReport := makeButton("/sendReport", "Сообщить о проблеме")
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReport))
+//askQuestion sends the question to the user and starts its timer
+func (h *callBackQueryHandler) askQuestion(user *model.User, question *model.Question) {
+	user.StartQuestion(question)
+	if question.IsQuiz() {
+		message, pollID, err := sendQuiz(h.sender, user, question, questionMarkup(user))
+		if err != nil {
+			h.logger.Warnf("Can not send question \"%d\" as a quiz to user \"%d\": %s", question.ID, user.UserId, err)
+		} else {
+			user.QuestionMessage, user.QuestionPoll = message, pollID
+		}
+	}
 
-		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if user.QuestionPoll == "" {
+		user.QuestionMessage = sendQuestionMessage(h.sender, user.UserID(), question, questionText(user), questionMarkup(user))
+	}
 
-		user.QuestionMessage, _ = h.bot.Send(msg)
+	if user.Timer > 0 {
+		h.startCountdown(user, question, user.QuestionMessage)
 	}
 }
 
 func (h *callBackQueryHandler) handleShowAnswer() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'ShowAnswer'")
 	return func(user *model.User, u *tgbotapi.Update) {
-		msg := tgbotapi.NewEditMessageText(u.CallbackQuery.Message.Chat.ID, user.QuestionMessage.MessageID, user.Question.Answer)
+		if user.Question == nil {
+			h.unavailableCommand(user)
+			return
+		}
 
-		var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+		if !user.CloseQuestion(user.Question) {
+			h.showAnswer(user)
+			return
+		}
+
+		metrics.Answers.WithLabelValues("revealed").Inc()
+		finished := recordSessionResult(user, model.SessionMissed)
+		h.store.Review().AddCard(user.UserId, user.Question)
+		h.showAnswer(user)
+		if finished {
+			sendSessionSummary(h.sender, h.bot, user)
+		}
+	}
+}
+
+//showAnswer replaces the question message with the answer
+func (h *callBackQueryHandler) showAnswer(user *model.User) {
+	user.AnswerQuestion()
 
-		btnQuestion := makeButton("/showQuestion", "Показать вопрос")
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	// A closed quiz poll keeps showing the question, the correct option and the comment
+	if user.QuestionPoll == "" {
+		btnQuestion := makeButton("/showQuestion", user.Tr("question.show_question"))
 		if user.Question.Comment != "" {
-			btnComment := makeButton("/showComment", "Показать комментарий")
+			btnComment := makeButton("/showComment", user.Tr("question.show_comment"))
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnQuestion, btnComment))
 		} else {
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnQuestion))
 		}
+	}
+
+	// The question is kept so that the shared link can open it
+	key := h.store.Question().Add(user.Question)
+	link := shareURL(questionLink(h.bot, key), user.Tr("question.share_text"))
+	btnFavorite := makeButton("/favorite", user.Tr("favorites.add"))
+	btnShare := tgbotapi.NewInlineKeyboardButtonURL(user.Tr("question.share"), link)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnFavorite, btnShare))
 
-		btnReport := makeButton("/sendReport", "Сообщить о проблеме")
-		btnGetQuestion := makeButton("/getQuestion", "Следующий вопрос")
+	// A reviewed question is graded by the user instead of going to the next one
+	if user.ReviewCard != nil {
+		rows = append(rows, reviewGradeRow(user))
+	} else {
+		btnReport := makeButton("/sendReport", user.Tr("question.report"))
+		btnGetQuestion := makeButton("/getQuestion", user.Tr("question.next"))
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReport, btnGetQuestion))
+	}
 
-		replyMarkup := tgbotapi.NewInlineKeyboardMarkup(rows...)
-		msg.ReplyMarkup = &replyMarkup
+	markup := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if user.QuestionPoll != "" {
+		// Telegram closes a timed poll itself, a closed poll only gets the keyboard
+		if !user.TimeIsUp() {
+			err := stopQuiz(h.sender, user.UserID(), user.QuestionMessage, markup)
+			if err == nil {
+				return
+			}
+			h.logger.Warnf("Can not stop the quiz of user \"%d\": %s", user.UserId, err)
+		}
 
-		h.bot.Send(msg)
+		h.sender.Send(tgbotapi.NewEditMessageReplyMarkup(user.UserID(), user.QuestionMessage.MessageID, markup))
+		return
+	}
+
+	msg := editQuestionMessage(user.UserID(), user.QuestionMessage, user.Question.Answer, user.Question.ParseMode(), markup)
+	h.sender.Send(msg)
+}
+
+func (h *callBackQueryHandler) handleSendReport() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SendReport'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		requestReport(h.sender, h.store, user, user.Question)
 	}
 }
 
 func (h *callBackQueryHandler) handleShowQuestion() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'ShowQuestion'")
 	return func(user *model.User, u *tgbotapi.Update) {
-		msg := tgbotapi.NewEditMessageText(u.CallbackQuery.Message.Chat.ID, user.QuestionMessage.MessageID, user.Question.Question)
+		if user.Question == nil {
+			h.unavailableCommand(user)
+			return
+		}
 
 		var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
 
-		btnAnswer := makeButton("/showAnswer", "Показать ответ")
+		btnAnswer := makeButton("/showAnswer", user.Tr("question.show_answer"))
 		if user.Question.Comment != "" {
-			btnComment := makeButton("/showComment", "Показать комментарий")
+			btnComment := makeButton("/showComment", user.Tr("question.show_comment"))
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAnswer, btnComment))
 		} else {
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAnswer))
 		}
 
-		btnReport := makeButton("/sendReport", "Сообщить о проблеме")
-		btnGetQuestion := makeButton("/getQuestion", "Следующий вопрос")
+		btnReport := makeButton("/sendReport", user.Tr("question.report"))
+		btnGetQuestion := makeButton("/getQuestion", user.Tr("question.next"))
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReport, btnGetQuestion))
 
-		replyMarkup := tgbotapi.NewInlineKeyboardMarkup(rows...)
-		msg.ReplyMarkup = &replyMarkup
-
-		h.bot.Send(msg)
+		msg := editQuestionMessage(user.UserID(), user.QuestionMessage, user.Question.Question, user.Question.ParseMode(), tgbotapi.NewInlineKeyboardMarkup(rows...))
+		h.sender.Send(msg)
 	}
 }
 
 func (h *callBackQueryHandler) handleShowComment() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'ShowComment'")
 	return func(user *model.User, u *tgbotapi.Update) {
-		msg := tgbotapi.NewEditMessageText(u.CallbackQuery.Message.Chat.ID, user.QuestionMessage.MessageID, user.Question.Comment)
+		if user.Question == nil {
+			h.unavailableCommand(user)
+			return
+		}
 
 		var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
 
-		btnAnswer := makeButton("/showAnswer", "Показать ответ")
+		btnAnswer := makeButton("/showAnswer", user.Tr("question.show_answer"))
 		if user.Question.Comment != "" {
-			btnComment := makeButton("/showComment", "Показать комментарий")
+			btnComment := makeButton("/showComment", user.Tr("question.show_comment"))
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAnswer, btnComment))
 		} else {
 			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnAnswer))
 		}
 
-		btnReport := makeButton("/sendReport", "Сообщить о проблеме")
-		btnGetQuestion := makeButton("/getQuestion", "Следующий вопрос")
+		btnReport := makeButton("/sendReport", user.Tr("question.report"))
+		btnGetQuestion := makeButton("/getQuestion", user.Tr("question.next"))
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReport, btnGetQuestion))
 
-		replyMarkup := tgbotapi.NewInlineKeyboardMarkup(rows...)
-		msg.ReplyMarkup = &replyMarkup
-
-		h.bot.Send(msg)
+		msg := editQuestionMessage(user.UserID(), user.QuestionMessage, user.Question.Comment, user.Question.ParseMode(), tgbotapi.NewInlineKeyboardMarkup(rows...))
+		h.sender.Send(msg)
 
 	}
 }
@@ -259,7 +374,7 @@ func (h *callBackQueryHandler) handleShowComment() router.RouterHandler {
 func (h *callBackQueryHandler) handleGetMathProblem() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'GetMathProblem'")
 	return func(user *model.User, u *tgbotapi.Update) {
-		h.unavailableCommand(user.UserID())
+		h.unavailableCommand(user)
 	}
 }
 
@@ -269,7 +384,7 @@ func (h *callBackQueryHandler) handleSettings() router.RouterHandler {
 		message := model.GameMainSettingsMessage(user)
 		message.Prev = user.PlayMessageHead
 		user.PlayMessageHead = message
-		h.bot.Send(message.Msg)
+		h.sender.Send(message.Msg)
 	}
 }
 
@@ -278,7 +393,7 @@ func (h *callBackQueryHandler) handleBack() router.RouterHandler {
 	return func(user *model.User, u *tgbotapi.Update) {
 		message := user.PlayMessageHead.Prev
 		user.PlayMessageHead = message
-		h.bot.Send(message.Msg)
+		h.sender.Send(message.Msg)
 	}
 }
 
@@ -288,7 +403,7 @@ func (h *callBackQueryHandler) handleSubscriptions() router.RouterHandler {
 		message := model.GameSubscriptionsSettingsMessage(user)
 		message.Prev = user.PlayMessageHead
 		user.PlayMessageHead = message
-		h.bot.Send(message.Msg)
+		h.sender.Send(message.Msg)
 	}
 }
 
@@ -296,11 +411,49 @@ func (h *callBackQueryHandler) handleSetFirstName() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'SetFirstName'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
-		msg := tgbotapi.NewMessage(user.UserID(), "Окей, введите новое имя")
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("profile.enter_first_name"))
 
 		user.WriteTo = &user.FirstName
 
-		h.bot.Send(msg)
+		h.sender.Send(msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleLanguage() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Language'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		message := model.LanguageMessage(user)
+		h.sender.Send(message.Msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleSetLanguage() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SetLanguage'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 {
+			h.unavailableCommand(user)
+			return
+		}
+
+		switch lang := args[0]; {
+		case lang == "auto":
+			user.Language = ""
+		case locale.IsSupported(lang):
+			user.Language = lang
+		default:
+			h.unavailableCommand(user)
+			return
+		}
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("profile.language_changed"))
+		h.sender.Send(msg)
+
+		message := profileMessage(h.store, user)
+		user.ProfileMessageHead = message
+		user.ProfileMessage, _ = h.sender.Send(message.Msg)
 	}
 }
 
@@ -308,11 +461,11 @@ func (h *callBackQueryHandler) handleSetUserName() router.RouterHandler {
 	h.logger.Debugf("Register callback handler 'SetUserName'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
-		msg := tgbotapi.NewMessage(user.UserID(), "Окей, введите новое имя пользователя")
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("profile.enter_user_name"))
 
 		user.WriteTo = &user.UserName
 
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 	}
 }
 
@@ -365,15 +518,15 @@ func (b *tgbot) handleCallbackQuery(update *tgbotapi.Update) {
 }
 */
 
-func (h *callBackQueryHandler) unavailableCommand(chatID int64) {
-	err := errors.New("Недоступная команда")
-	h.internalError(chatID, err)
+func (h *callBackQueryHandler) unavailableCommand(user *model.User) {
+	err := errors.New(user.Tr("error.unavailable_command"))
+	h.internalError(user, err)
 }
 
-func (h *callBackQueryHandler) internalError(chatID int64, err error) {
-	errorMessage := fmt.Sprintf("Произошла внутренняя ошибка:\n\"%s\"\nПожалуйста, повторите попытку позже.", err)
-	msg := tgbotapi.NewMessage(chatID, errorMessage)
-	h.bot.Send(msg)
+func (h *callBackQueryHandler) internalError(user *model.User, err error) {
+	errorMessage := user.Tr("error.internal", err)
+	msg := tgbotapi.NewMessage(user.UserID(), errorMessage)
+	h.sender.Send(msg)
 }
 
 func makeButton(callbackData string, label string) tgbotapi.InlineKeyboardButton {
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/antiflood"
+
+	"github.com/BurntSushi/toml"
+)
+
+//Config ...
+type Config struct {
+	Token          string            `toml:"token"`
+	LogLevel       string            `toml:"log_level"`
+	QaskURL        string            `toml:"qask_url"`
+	QuestionSource string            `toml:"question_source"`
+	PacksDir       string            `toml:"packs_dir"`
+	Prefetch       int               `toml:"prefetch"`
+	HTTPAddr       string            `toml:"http_addr"`
+	Admins         []int             `toml:"admins"`
+	Categories     []string          `toml:"categories"`
+	AntiFlood      *antiflood.Config `toml:"antiflood"`
+	path           string
+}
+
+//NewConfig returns the default config, settings of the config file override the defaults
+func NewConfig() *Config {
+	return &Config{
+		LogLevel:       "debug",
+		QaskURL:        "http://172.20.0.3:30001",
+		QuestionSource: sourceQask,
+		Prefetch:       3,
+		AntiFlood:      antiflood.NewConfig(),
+	}
+}
+
+//LoadConfig reads the config file, it can be read again with "/admin reload"
+func LoadConfig(path string) (*Config, error) {
+	config := NewConfig()
+	if _, err := toml.DecodeFile(path, config); err != nil {
+		return nil, err
+	}
+
+	if err := config.AntiFlood.Validate(); err != nil {
+		return nil, err
+	}
+	config.path = path
+
+	return config, nil
+}
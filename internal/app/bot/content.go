@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/sender"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//maxCaptionLength is the limit of telegram for captions of media messages
+const maxCaptionLength = 1024
+
+//sendQuestionMessage sends the text of the question with its media. The text becomes the caption of the media
+//if it fits, otherwise it follows the media in a separate message. The returned message is the one with the keyboard
+func sendQuestionMessage(s *sender.Sender, chatID int64, q *model.Question, text string, markup tgbotapi.InlineKeyboardMarkup) tgbotapi.Message {
+	if q.Media == nil {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = q.ParseMode()
+		msg.ReplyMarkup = markup
+		message, _ := s.Send(msg)
+		return message
+	}
+
+	if utf8.RuneCountInString(text) > maxCaptionLength {
+		s.Send(mediaMessage(chatID, q.Media, "", "", nil))
+
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ParseMode = q.ParseMode()
+		msg.ReplyMarkup = markup
+		message, _ := s.Send(msg)
+		return message
+	}
+
+	message, _ := s.Send(mediaMessage(chatID, q.Media, text, q.ParseMode(), markup))
+	return message
+}
+
+//mediaMessage is a message with the media, a local file is uploaded and a URL is passed to telegram
+func mediaMessage(chatID int64, media *model.Media, caption string, parseMode string, markup interface{}) tgbotapi.Chattable {
+	file := interface{}(media.File)
+	upload := media.File != ""
+
+	switch media.Type {
+	case model.MediaAudio:
+		msg := tgbotapi.NewAudioShare(chatID, media.URL)
+		if upload {
+			msg = tgbotapi.NewAudioUpload(chatID, file)
+		}
+		msg.Caption, msg.ParseMode, msg.ReplyMarkup = caption, parseMode, markup
+		return msg
+	case model.MediaDocument:
+		msg := tgbotapi.NewDocumentShare(chatID, media.URL)
+		if upload {
+			msg = tgbotapi.NewDocumentUpload(chatID, file)
+		}
+		msg.Caption, msg.ParseMode, msg.ReplyMarkup = caption, parseMode, markup
+		return msg
+	}
+
+	msg := tgbotapi.NewPhotoShare(chatID, media.URL)
+	if upload {
+		msg = tgbotapi.NewPhotoUpload(chatID, file)
+	}
+	msg.Caption, msg.ParseMode, msg.ReplyMarkup = caption, parseMode, markup
+	return msg
+}
+
+//editQuestionMessage replaces the text of a question message, the caption is edited for media messages
+func editQuestionMessage(chatID int64, message tgbotapi.Message, text string, parseMode string, markup tgbotapi.InlineKeyboardMarkup) tgbotapi.Chattable {
+	if message.Photo != nil || message.Audio != nil || message.Document != nil {
+		// A plain text can be shortened safely, a formatted one would lose its closing tags
+		if parseMode == "" && utf8.RuneCountInString(text) > maxCaptionLength {
+			text = string([]rune(text)[:maxCaptionLength-1]) + "…"
+		}
+
+		msg := tgbotapi.NewEditMessageCaption(chatID, message.MessageID, text)
+		msg.ParseMode = parseMode
+		msg.ReplyMarkup = &markup
+		return msg
+	}
+
+	msg := tgbotapi.NewEditMessageText(chatID, message.MessageID, text)
+	msg.ParseMode = parseMode
+	msg.ReplyMarkup = &markup
+	return msg
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"fmt"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const (
+	//favoritesPageSize is the number of favorite questions on a page
+	favoritesPageSize = 5
+	//historyLength is the number of the latest questions shown by /history
+	historyLength = 10
+	//maxListTextRunes shortens questions and answers in lists, so that a list fits into a message
+	maxListTextRunes = 150
+)
+
+//listEntry is a question and its answer in a list of questions, lists are sent as HTML
+func listEntry(user *model.User, q *model.Question) string {
+	return q.HTML(q.Question, maxListTextRunes) + "\n" + user.Tr("favorites.answer", q.HTML(q.Answer, maxListTextRunes))
+}
+
+//favoritesPage is a text and a keyboard of a page of favorite questions, pages start from 1
+func favoritesPage(user *model.User, questions []*model.Question, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	pages := (len(questions) + favoritesPageSize - 1) / favoritesPageSize
+	if page > pages {
+		page = pages
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	var b strings.Builder
+	b.WriteString(user.Tr("favorites.title", page, pages))
+	for i := (page - 1) * favoritesPageSize; i < len(questions) && i < page*favoritesPageSize; i++ {
+		fmt.Fprintf(&b, "\n\n%d. %s", i+1, listEntry(user, questions[i]))
+	}
+
+	var row []tgbotapi.InlineKeyboardButton
+	if page > 1 {
+		row = append(row, makeButton("/favoritesPage "+strconv.Itoa(page-1), "◀"))
+	}
+	if page < pages {
+		row = append(row, makeButton("/favoritesPage "+strconv.Itoa(page+1), "▶"))
+	}
+
+	// A single page has no buttons, an empty keyboard is sent rather than null
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0)
+	if len(row) != 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(row...))
+	}
+
+	return b.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (h *messageHandler) handleFavorites() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Favorites'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		questions := h.store.History().Favorites(user.UserId)
+		if len(questions) == 0 {
+			h.sendText(user.UserID(), user.Tr("favorites.empty"))
+			return
+		}
+
+		text, markup := favoritesPage(user, questions, 1)
+		msg := tgbotapi.NewMessage(user.UserID(), text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.ReplyMarkup = markup
+		h.sender.Send(msg)
+	}
+}
+
+func (h *messageHandler) handleHistory() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'History'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		entries := h.store.History().Recent(user.UserId, historyLength)
+		if len(entries) == 0 {
+			h.sendText(user.UserID(), user.Tr("history.empty"))
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString(user.Tr("history.title", len(entries)))
+		for _, entry := range entries {
+			mark := "❌"
+			if entry.Correct {
+				mark = "✅"
+			}
+
+			fmt.Fprintf(&b, "\n\n%s %s", mark, listEntry(user, entry.Question))
+		}
+
+		msg := tgbotapi.NewMessage(user.UserID(), b.String())
+		msg.ParseMode = tgbotapi.ModeHTML
+		h.sender.Send(msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleFavorite() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Favorite'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if user.Question == nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		key := "favorites.added"
+		if !h.store.History().AddFavorite(user.UserId, user.Question) {
+			key = "favorites.already_added"
+		}
+		h.store.Review().AddCard(user.UserId, user.Question)
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr(key)))
+	}
+}
+
+func (h *callBackQueryHandler) handleFavoritesPage() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'FavoritesPage'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 {
+			h.unavailableCommand(user)
+			return
+		}
+
+		page, err := strconv.Atoi(args[0])
+		if err != nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		text, markup := favoritesPage(user, h.store.History().Favorites(user.UserId), page)
+		msg := tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, text)
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.ReplyMarkup = &markup
+		h.sender.Send(msg)
+	}
+}
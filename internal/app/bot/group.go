@@ -0,0 +1,223 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/source"
+	"qask_telegram/internal/app/store"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/sirupsen/logrus"
+)
+
+const scoreboardLimit = 10
+
+//groupHandler serves messages and callbacks from group chats,
+//where the chat is shared by many users and questions are answered by any member.
+//Answers are plain messages, so the privacy mode of the bot has to be disabled with @BotFather,
+//otherwise telegram sends the bot only commands and replies to it
+type groupHandler struct {
+	bot       *tgbotapi.BotAPI
+	sender    *sender.Sender
+	questions *source.Prefetcher
+	logger    *logrus.Logger
+	router    *router.Router
+	store     store.Store
+}
+
+func newGroupHandler(b *tgbot) *groupHandler {
+	gH := &groupHandler{
+		bot:       b.bot,
+		sender:    b.sender,
+		questions: b.questions,
+		logger:    b.logger,
+		router:    router.NewRouter("group", b.bot.Self.UserName, b.logger),
+		store:     b.store,
+	}
+
+	gH.configureRouter()
+
+	return gH
+}
+
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+func (h *groupHandler) configureRouter() {
+	h.logger.Debugf("Configuring group commands router ...")
+	// Registering new routes (path, isPublic, handler)
+	h.router.NewRoute("/help", true, h.handleHelp())
+	h.router.NewRoute("/start", true, h.handleHelp())
+	h.router.NewRoute("/question", true, h.handleQuestion())
+	h.router.NewRoute("/showAnswer", true, h.handleShowAnswer())
+	h.router.NewRoute("/scores", true, h.handleScores())
+	h.logger.Debugf("Configuring group commands router done")
+}
+
+//group returns the group of the update's chat, creating it on the first update
+func (h *groupHandler) group(u *tgbotapi.Update) *model.Group {
+	chat, from := u.Message.Chat, u.Message.From
+	if u.CallbackQuery != nil {
+		chat, from = u.CallbackQuery.Message.Chat, u.CallbackQuery.From
+	}
+
+	if group := h.store.Group().FindGroup(chat.ID); group != nil {
+		return group
+	}
+
+	group := h.store.Group().CreateGroup(chat.ID)
+	group.Title = chat.Title
+	group.LanguageCode = from.LanguageCode
+
+	return group
+}
+
+func (h *groupHandler) handleMessage(u *tgbotapi.Update) {
+	group := h.group(u)
+
+	if u.Message.NewChatMembers != nil {
+		for _, member := range *u.Message.NewChatMembers {
+			if member.ID == h.bot.Self.ID {
+				h.logger.Infof("Bot added to group \"%d\" \"%s\"", group.ChatID, group.Title)
+				h.sendText(group.ChatID, group.Tr("group.help"))
+				return
+			}
+		}
+	}
+
+	if u.Message.Text == "" {
+		return
+	}
+
+	question := group.TryAnswer(u.Message.From, u.Message.Text)
+	if question == nil {
+		return
+	}
+
+	metrics.Answers.WithLabelValues("correct").Inc()
+	h.logger.Infof("User \"%d\" answered correctly in group \"%d\"", u.Message.From.ID, group.ChatID)
+
+	text := group.Tr("group.correct", question.Escape(u.Message.From.FirstName), question.Answer)
+	if question.Comment != "" {
+		text += "\n\n" + question.Comment
+	}
+
+	msg := tgbotapi.NewMessage(group.ChatID, text)
+	msg.ParseMode = question.ParseMode()
+	msg.ReplyToMessageID = u.Message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/question", group.Tr("question.next"))))
+	h.sender.Send(msg)
+}
+
+func (h *groupHandler) handleCommand(u *tgbotapi.Update) {
+	command, from := u.Message.Text, u.Message.From
+	if u.CallbackQuery != nil {
+		command, from = u.CallbackQuery.Data, u.CallbackQuery.From
+	}
+
+	group := h.group(u)
+	h.logger.Infof("Received Group Command: command=\"%s\" chatId=\"%d\"", command, group.ChatID)
+
+	user := h.store.User().FindUser(from.ID)
+	if handler := h.router.GetHandler(command); handler != nil {
+		handler(user, u)
+	}
+}
+
+func (h *groupHandler) updateIsCommand(u *tgbotapi.Update) bool {
+	if u.CallbackQuery != nil {
+		return strings.HasPrefix(u.CallbackQuery.Data, "/")
+	}
+
+	return strings.HasPrefix(u.Message.Text, "/")
+}
+
+func (h *groupHandler) handleHelp() router.RouterHandler {
+	h.logger.Debugf("Register group handler 'Help'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		group := h.group(u)
+		h.sendText(group.ChatID, group.Tr("group.help"))
+	}
+}
+
+func (h *groupHandler) handleQuestion() router.RouterHandler {
+	h.logger.Debugf("Register group handler 'Question'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		group := h.group(u)
+
+		question, err := nextQuestion(context.Background(), h.questions, h.store, group.ChatID, model.QuestionFilter{})
+		if err != nil {
+			h.logger.Errorf("Can not get question for group \"%d\": %s", group.ChatID, err)
+			h.sendText(group.ChatID, group.Tr(questionErrorKey(err)))
+			return
+		}
+
+		group.SetQuestion(question)
+		metrics.QuestionsServed.Inc()
+
+		markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/showAnswer", group.Tr("question.show_answer"))))
+		group.QuestionMessage = sendQuestionMessage(h.sender, group.ChatID, question, group.Tr("group.question", question.Question), markup)
+	}
+}
+
+func (h *groupHandler) handleShowAnswer() router.RouterHandler {
+	h.logger.Debugf("Register group handler 'ShowAnswer'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		group := h.group(u)
+
+		question := group.CloseQuestion()
+		if question == nil {
+			h.sendText(group.ChatID, group.Tr("group.no_question"))
+			return
+		}
+
+		text := group.Tr("group.answer", question.Question, question.Answer)
+		if question.Comment != "" {
+			text += "\n\n" + question.Comment
+		}
+
+		markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/question", group.Tr("question.next"))))
+		h.sender.Send(editQuestionMessage(group.ChatID, group.QuestionMessage, text, question.ParseMode(), markup))
+	}
+}
+
+func (h *groupHandler) handleScores() router.RouterHandler {
+	h.logger.Debugf("Register group handler 'Scores'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		group := h.group(u)
+
+		scores := group.Scoreboard()
+		if len(scores) == 0 {
+			h.sendText(group.ChatID, group.Tr("group.scores_empty"))
+			return
+		}
+
+		if len(scores) > scoreboardLimit {
+			scores = scores[:scoreboardLimit]
+		}
+
+		var b strings.Builder
+		b.WriteString(group.Tr("group.scores"))
+		for i, score := range scores {
+			fmt.Fprintf(&b, "\n%d. %s — %s", i+1, score.Name, locale.Plural(group.Lang(), "points", score.Points, score.Points))
+		}
+
+		h.sendText(group.ChatID, b.String())
+	}
+}
+
+func (h *groupHandler) sendText(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	h.sender.Send(msg)
+}
@@ -0,0 +1,41 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//handleHint gives the next hint to the current question: the length of the answer first,
+//then the first letter and one more letter with each hint
+func (h *callBackQueryHandler) handleHint() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Hint'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if user.Question == nil || user.QuestionAnswered {
+			h.unavailableCommand(user)
+			return
+		}
+
+		if user.Hints >= user.Question.MaxHints() {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("hint.no_more")))
+			return
+		}
+
+		user.Hints++
+
+		letters := user.Question.AnswerLetters()
+		text := user.Tr("hint.length", locale.Plural(user.Lang(), "letters", letters, letters))
+		if user.Hints > 1 {
+			text = user.Tr("hint.letters")
+		}
+		text += "\n" + user.Question.MaskAnswer(user.Hints-1)
+
+		points := model.QuestionPoints(user.Hints)
+		text += "\n\n" + user.Tr("hint.points", locale.Plural(user.Lang(), "points", points, points))
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), text))
+	}
+}
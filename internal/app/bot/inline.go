@@ -0,0 +1,220 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/source"
+	"qask_telegram/internal/app/store"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	inlineResults       = 5
+	maxInlineTitleRunes = 64
+	//inlineCacheTime is how long telegram may reuse the results of the same query, in seconds
+	inlineCacheTime = 5
+	//inlineTimeout limits getting all questions of an inline query, telegram expires a query after a few seconds
+	inlineTimeout = 3 * time.Second
+)
+
+//inlineHandler serves inline queries "@bot text" from any chat and callbacks of the messages posted with them.
+//Such messages belong to chats the bot may not be a member of, so their callbacks have no message.
+//Inline mode has to be enabled with @BotFather
+type inlineHandler struct {
+	bot       *tgbotapi.BotAPI
+	sender    *sender.Sender
+	questions *source.Prefetcher
+	logger    *logrus.Logger
+	router    *router.Router
+	store     store.Store
+}
+
+func newInlineHandler(b *tgbot) *inlineHandler {
+	iH := &inlineHandler{
+		bot:       b.bot,
+		sender:    b.sender,
+		questions: b.inlineQuestions,
+		logger:    b.logger,
+		router:    router.NewRouter("inline", b.bot.Self.UserName, b.logger),
+		store:     b.store,
+	}
+
+	iH.configureRouter()
+
+	return iH
+}
+
+func isInlineUpdate(u *tgbotapi.Update) bool {
+	if u.CallbackQuery != nil {
+		return u.CallbackQuery.Message == nil && u.CallbackQuery.InlineMessageID != ""
+	}
+
+	return u.InlineQuery != nil || u.ChosenInlineResult != nil
+}
+
+func (h *inlineHandler) configureRouter() {
+	h.logger.Debugf("Configuring inline commands router ...")
+	// Registering new routes (path, isPublic, handler)
+	h.router.NewRoute("/inlineAnswer", true, h.handleShowAnswer())
+	h.logger.Debugf("Configuring inline commands router done")
+}
+
+//lang returns the language of a registered user or of the telegram client
+func (h *inlineHandler) lang(from *tgbotapi.User) string {
+	if user := h.store.User().FindUser(from.ID); user != nil {
+		return user.Lang()
+	}
+
+	return locale.Normalize(from.LanguageCode)
+}
+
+func (h *inlineHandler) handleMessage(u *tgbotapi.Update) {
+	if u.InlineQuery != nil {
+		h.answerInlineQuery(u.InlineQuery)
+	} else if u.ChosenInlineResult != nil {
+		// Telegram sends chosen results only if inline feedback is enabled with @BotFather
+		metrics.QuestionsShared.Inc()
+		h.logger.Infof("User \"%d\" shared question \"%s\" inline", u.ChosenInlineResult.From.ID, u.ChosenInlineResult.ResultID)
+	}
+}
+
+func (h *inlineHandler) handleCommand(u *tgbotapi.Update) {
+	h.logger.Infof("Received Inline Command: command=\"%s\" inlineMessageId=\"%s\"", u.CallbackQuery.Data, u.CallbackQuery.InlineMessageID)
+
+	user := h.store.User().FindUser(u.CallbackQuery.From.ID)
+	if handler := h.router.GetHandler(u.CallbackQuery.Data); handler != nil {
+		handler(user, u)
+	}
+}
+
+func (h *inlineHandler) updateIsCommand(u *tgbotapi.Update) bool {
+	return u.CallbackQuery != nil && strings.HasPrefix(u.CallbackQuery.Data, "/")
+}
+
+//answerInlineQuery offers random questions or, if there is a query text, questions containing it
+func (h *inlineHandler) answerInlineQuery(query *tgbotapi.InlineQuery) {
+	lang := h.lang(query.From)
+	filter := model.QuestionFilter{
+		Search: strings.TrimSpace(query.Query),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), inlineTimeout)
+	defer cancel()
+
+	results := make([]interface{}, 0, inlineResults)
+	offered := make(map[string]bool)
+	// Every attempt may return a question offered already or one that can not be posted as text
+	for i := 0; i < 2*inlineResults && len(results) < inlineResults; i++ {
+		q, err := h.questions.GetQuestion(ctx, int64(query.From.ID), filter)
+		if err != nil {
+			if err != source.ErrNoQuestions {
+				h.logger.Errorf("Can not get inline questions for user \"%d\": %s", query.From.ID, err)
+			}
+			break
+		}
+
+		// Questions without an ID are told apart by the text
+		if q.Media != nil || offered[q.Question] {
+			continue
+		}
+
+		offered[q.Question] = true
+		if q.ID != 0 {
+			filter.Exclude = append(filter.Exclude, q.ID)
+		}
+		key := h.store.Question().Add(q)
+		results = append(results, inlineResult(lang, key, q))
+	}
+
+	config := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheTime,
+	}
+
+	if _, err := h.bot.AnswerInlineQuery(config); err != nil {
+		h.logger.Errorf("Can not answer inline query of user \"%d\": %s", query.From.ID, err)
+	}
+}
+
+//inlineResult is a question posted to a chat with a button showing the answer to anybody in the chat,
+//the key is the question's key in the shared questions
+func inlineResult(lang string, key int, q *model.Question) tgbotapi.InlineQueryResultArticle {
+	title := []rune(q.Question)
+	if len(title) > maxInlineTitleRunes {
+		title = append(title[:maxInlineTitleRunes-1], '…')
+	}
+
+	text := inlineQuestionText(lang, q)
+	article := tgbotapi.NewInlineQueryResultArticle(strconv.Itoa(key), string(title), text)
+	article.InputMessageContent = tgbotapi.InputTextMessageContent{
+		Text:      text,
+		ParseMode: q.ParseMode(),
+	}
+	article.Description = q.Category
+
+	btnAnswer := makeButton(fmt.Sprintf("/inlineAnswer %d", key), locale.Get(lang, "question.show_answer"))
+	markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnAnswer))
+	article.ReplyMarkup = &markup
+
+	return article
+}
+
+//inlineQuestionText is the question with its options if it is a multiple-choice one
+func inlineQuestionText(lang string, q *model.Question) string {
+	text := locale.Get(lang, "inline.question", q.Question)
+	for i, option := range q.Options {
+		text += fmt.Sprintf("\n%d) %s", i+1, q.Escape(option))
+	}
+
+	return text
+}
+
+func (h *inlineHandler) handleShowAnswer() router.RouterHandler {
+	h.logger.Debugf("Register inline handler 'ShowAnswer'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		callback := u.CallbackQuery
+		lang := h.lang(callback.From)
+
+		_, args := router.ParseCommand(callback.Data)
+		var q *model.Question
+		if len(args) == 1 {
+			if key, err := strconv.Atoi(args[0]); err == nil {
+				q = h.store.Question().Find(key)
+			}
+		}
+
+		// The questions are kept in memory, so the ones posted before a restart or long ago are lost
+		if q == nil {
+			h.bot.AnswerCallbackQuery(tgbotapi.NewCallbackWithAlert(callback.ID, locale.Get(lang, "inline.expired")))
+			return
+		}
+
+		text := locale.Get(lang, "group.answer", inlineQuestionText(lang, q), q.Answer)
+		if q.Comment != "" {
+			text += "\n\n" + q.Comment
+		}
+		text += "\n\n" + q.Escape(locale.Get(lang, "inline.opened_by", callback.From.FirstName))
+
+		// tgbotapi fails to decode the result of inline message edits, which is not a message
+		params := url.Values{
+			"inline_message_id": {callback.InlineMessageID},
+			"text":              {text},
+			"parse_mode":        {q.ParseMode()},
+		}
+		if _, err := h.sender.Request("editMessageText", params, sender.PriorityHigh); err != nil {
+			h.logger.Errorf("Can not show the answer of inline message \"%s\": %s", callback.InlineMessageID, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"fmt"
+	"net/url"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//Payloads of deep links t.me/bot?start=<payload>
+const (
+	referralPrefix = "ref_"
+	questionPrefix = "q_"
+)
+
+//inviteLink is the deep link attributing users who start the bot with it to the user
+func inviteLink(bot *tgbotapi.BotAPI, user *model.User) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%d", bot.Self.UserName, referralPrefix, user.UserId)
+}
+
+//questionLink is the deep link opening the question with the key in the shared questions
+func questionLink(bot *tgbotapi.BotAPI, key int) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%d", bot.Self.UserName, questionPrefix, key)
+}
+
+//shareURL opens the telegram dialog forwarding the link with the text to any chat
+func shareURL(link string, text string) string {
+	return "https://t.me/share/url?url=" + url.QueryEscape(link) + "&text=" + url.QueryEscape(text)
+}
+
+//startPayload returns the payload of a deep link, "/start <payload>", or an empty string
+func startPayload(text string) string {
+	_, args := router.ParseCommand(text)
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+//attributeReferral remembers who invited a new user and lets the referrer know
+func (h *messageHandler) attributeReferral(user *model.User, payload string) {
+	if !strings.HasPrefix(payload, referralPrefix) {
+		return
+	}
+
+	referrerID, err := strconv.Atoi(strings.TrimPrefix(payload, referralPrefix))
+	if err != nil || referrerID == user.UserId {
+		return
+	}
+
+	referrer := h.store.User().FindUser(referrerID)
+	if referrer == nil {
+		return
+	}
+
+	user.ReferrerID = referrerID
+	h.logger.Infof("User \"%d\" is invited by user \"%d\"", user.UserId, referrerID)
+
+	pushTo(h.sender, referrer, tgbotapi.NewMessage(referrer.UserID(), referrer.Tr("invite.joined", user.FirstName)))
+}
+
+//openQuestion asks the question of a deep link, it can be found if it was shared since the last restart.
+//An unregistered user is asked the question after the registration
+func (h *messageHandler) openQuestion(user *model.User, payload string) {
+	var q *model.Question
+	if key, err := strconv.Atoi(strings.TrimPrefix(payload, questionPrefix)); err == nil {
+		q = h.store.Question().Find(key)
+	}
+
+	if q == nil {
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("invite.question_not_found")))
+		return
+	}
+
+	if !user.Registered {
+		user.SharedQuestion = q
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("invite.question_after_register")))
+		return
+	}
+
+	// The shared question would replace the open question of the game
+	if user.SessionQuestion() {
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("invite.question_in_session")))
+		return
+	}
+
+	h.askQuestion(user, q)
+}
+
+//askSharedQuestion asks a question opened by a link outside of the user's session
+func (h *callBackQueryHandler) askSharedQuestion(user *model.User, q *model.Question) {
+	user.HistoryEntry = h.store.History().AddQuestion(user.UserId, q)
+	user.ReviewCard = nil
+	metrics.QuestionsServed.Inc()
+
+	h.askQuestion(user, q)
+}
+
+func (h *messageHandler) handleInvite() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Invite'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		link := inviteLink(h.bot, user)
+		referrals := len(h.store.User().Referrals(user.UserId))
+
+		btnShare := tgbotapi.NewInlineKeyboardButtonURL(user.Tr("invite.share"), shareURL(link, user.Tr("invite.share_text")))
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("invite.text", link, referrals))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnShare))
+		msg.DisableWebPagePreview = true
+		h.sender.Send(msg)
+	}
+}
@@ -1,31 +1,52 @@
 package bot
 
 import (
+	"qask_telegram/internal/app/broadcast"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
 	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/qask"
 	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
 	"qask_telegram/internal/app/store"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/sirupsen/logrus"
 )
 
 type messageHandler struct {
-	bot    *tgbotapi.BotAPI
-	logger *logrus.Logger
-	router *router.Router
-	store  store.Store
+	bot         *tgbotapi.BotAPI
+	sender      *sender.Sender
+	qask        *qask.Client
+	admins      *adminList
+	broadcaster *broadcast.Broadcaster
+	reload      func() error
+	askQuestion func(*model.User, *model.Question)
+	logger      *logrus.Logger
+	router      *router.Router
+	adminRouter *router.Router
+	store       store.Store
 }
 
-func newMessageHandler(bot *tgbotapi.BotAPI, logger *logrus.Logger, store store.Store) *messageHandler {
+func newMessageHandler(b *tgbot) *messageHandler {
 	mH := &messageHandler{
-		bot:    bot,
-		logger: logger,
-		router: router.NewRouter(logger),
-		store:  store,
+		bot:         b.bot,
+		sender:      b.sender,
+		qask:        b.qask,
+		admins:      b.admins,
+		broadcaster: b.broadcaster,
+		reload:      b.reloadConfig,
+		askQuestion: b.callBackQueryHandler.askSharedQuestion,
+		logger:      b.logger,
+		router:      router.NewRouter("message", b.bot.Self.UserName, b.logger),
+		adminRouter: router.NewRouter("admin", b.bot.Self.UserName, b.logger),
+		store:       b.store,
 	}
 
 	mH.configureRouter()
+	mH.configureAdminRouter()
 
 	return mH
 }
@@ -38,19 +59,68 @@ func (h *messageHandler) configureRouter() {
 	h.router.NewRoute("/play", true, h.handlePlay())
 	h.router.NewRoute("/report", true, h.handleReport())
 	h.router.NewRoute("/profile", true, h.handleProfile())
+	h.router.NewRoute("/favorites", true, h.handleFavorites())
+	h.router.NewRoute("/history", true, h.handleHistory())
+	h.router.NewRoute("/invite", true, h.handleInvite())
+	h.router.NewRoute("/submit", true, h.handleSubmit())
+	h.router.NewRoute("/team", true, h.handleTeam())
+	h.router.NewRoute("/admin", true, h.handleAdmin(), h.adminOnly)
+	h.router.NewRoute("/broadcast", true, h.handleBroadcast(), h.adminOnly)
 	h.logger.Debugf("Configuring message commands router done")
 }
 
 func (h *messageHandler) handleMessage(u *tgbotapi.Update) {
 	h.logger.Infof("Received Message: text=\"%s\" chatId=\"%d\"", u.Message.Text, u.Message.Chat.ID)
 
-	chatID := u.Message.Chat.ID
-	user := h.store.User().FindUser(int(chatID))
+	user := h.store.User().FindUser(u.Message.From.ID)
+	if user == nil {
+		return
+	}
+
+	user.Seen(u.Message.From)
 
 	if user.WriteTo != nil {
 		*user.WriteTo = u.Message.Text
 		user.WriteTo = nil
+		return
 	}
+
+	if user.OnText != nil {
+		onText := user.OnText
+		user.OnText = nil
+		onText(u.Message.Text)
+		return
+	}
+
+	h.checkAnswer(user, u.Message.Text)
+}
+
+//checkAnswer scores a text answer to the current question, answers after the time limit are rejected
+func (h *messageHandler) checkAnswer(user *model.User, answer string) {
+	question := user.Question
+	if question == nil || user.QuestionAnswered {
+		return
+	}
+
+	if user.TimeIsUp() {
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("timer.too_late"))
+		h.sender.Send(msg)
+		return
+	}
+
+	if !question.CheckAnswer(answer) {
+		metrics.Answers.WithLabelValues("incorrect").Inc()
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("answer.incorrect"))
+		h.sender.Send(msg)
+		return
+	}
+
+	if !user.CloseQuestion(question) {
+		return
+	}
+
+	acceptAnswer(h.sender, h.bot, user)
 }
 
 func (h *messageHandler) handleCommand(u *tgbotapi.Update) {
@@ -58,23 +128,29 @@ func (h *messageHandler) handleCommand(u *tgbotapi.Update) {
 	chatID := u.Message.Chat.ID
 	h.logger.Infof("Received Message Command: command=\"%s\" chatId=\"%d\"", text, chatID)
 
-	user := h.store.User().FindUser(int(chatID))
+	user := h.store.User().FindUser(u.Message.From.ID)
+	lang := locale.Normalize(u.Message.From.LanguageCode)
 
 	if user == nil {
-		if u.Message.Text != "/start" && u.Message.Text != "/help" {
-			h.unavailableCommand(chatID)
+		command, _ := router.ParseCommand(text)
+		command, _ = router.SplitBotName(command)
+		if command != "/start" && command != "/help" {
+			h.unavailableCommand(chatID, lang)
 			return
 		}
+	} else {
+		user.Seen(u.Message.From)
+		lang = user.Lang()
 	}
 
 	if handler := h.router.GetHandler(u.Message.Text); handler != nil {
 		if h.router.CommandIsPublic(u.Message.Text) {
 			handler(user, u)
 		} else {
-			h.unavailableCommand(chatID)
+			h.unavailableCommand(chatID, lang)
 		}
 	} else {
-		h.unavailableCommand(chatID)
+		h.unavailableCommand(chatID, lang)
 	}
 }
 
@@ -85,37 +161,23 @@ func (h *messageHandler) updateIsCommand(u *tgbotapi.Update) bool {
 func (h *messageHandler) handleHelp() router.RouterHandler {
 	h.logger.Debugf("Register message handler 'Help'")
 
-	unregisteredHelpText :=
-		`Вам доступны следующие команды:
-/help - справка
-/start - регистрация
-`
-	unregisteredHelpMessage := tgbotapi.NewMessage(0, unregisteredHelpText)
-
-	registeredHelpText :=
-		`Вам доступны следующие команды:
-/play - играть
-/profile - настройки профиля
-/newpass - сгенерировать новый пароль
-`
-	registeredHelpMessage := tgbotapi.NewMessage(0, registeredHelpText)
-
 	return func(user *model.User, u *tgbotapi.Update) {
 		chatID := u.Message.Chat.ID
-		msg := unregisteredHelpMessage
-		msg.ChatID = chatID
+		lang := locale.Normalize(u.Message.From.LanguageCode)
+		msg := tgbotapi.NewMessage(chatID, locale.Get(lang, "help.unregistered"))
 
-		if user == nil {
-			msg = unregisteredHelpMessage
-			msg.ChatID = chatID
-		} else {
+		if user != nil {
+			msg.Text = user.Tr("help.unregistered")
 			if user.IsRegistered() == true {
-				msg = registeredHelpMessage
-				msg.ChatID = int64(user.UserId)
+				msg = tgbotapi.NewMessage(int64(user.UserId), user.Tr("help.registered"))
+			}
+
+			if h.admins.contains(user.UserId) {
+				msg.Text += "\n" + user.Tr("help.admin")
 			}
 		}
 
-		h.bot.Send(msg)
+		h.sender.Send(msg)
 	}
 }
 
@@ -123,6 +185,8 @@ func (h *messageHandler) handleStart() router.RouterHandler {
 	h.logger.Debugf("Register message handler 'Start'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
+		payload := startPayload(u.Message.Text)
+
 		if user == nil {
 			h.logger.Infof("Register new user: ID=\"%d\" FirstName=\"%s\" LastName=\"%s\" UserName \"%s\" LanguageCode=\"%s\" IsBot=\"%t\"",
 				u.Message.From.ID,
@@ -135,20 +199,26 @@ func (h *messageHandler) handleStart() router.RouterHandler {
 			user = h.store.User().CreateUser(u.Message.From.ID)
 			user.FirstName = u.Message.From.FirstName
 			user.UserName = u.Message.From.UserName
-
-			message := model.WelcomeMessage(user)
-			user.WelcomeMessageHead = message
-			user.WelcomeMessage, _ = h.bot.Send(message.Msg)
-		} else {
-			if user.Registered {
-				msg := tgbotapi.NewMessage(user.UserID(), "Вы уже зарегистрированы!")
-				h.bot.Send(msg)
+			user.Seen(u.Message.From)
+			h.attributeReferral(user, payload)
+		} else if user.Registered {
+			if strings.HasPrefix(payload, questionPrefix) {
+				h.openQuestion(user, payload)
 			} else {
-				message := model.WelcomeMessage(user)
-				user.WelcomeMessageHead = message
-				user.WelcomeMessage, _ = h.bot.Send(message.Msg)
+				msg := tgbotapi.NewMessage(user.UserID(), user.Tr("start.already_registered"))
+				h.sender.Send(msg)
 			}
+			return
 		}
+
+		// A question shared with a new user is kept until the registration
+		if strings.HasPrefix(payload, questionPrefix) {
+			h.openQuestion(user, payload)
+		}
+
+		message := model.WelcomeMessage(user)
+		user.WelcomeMessageHead = message
+		user.WelcomeMessage, _ = h.sender.Send(message.Msg)
 	}
 }
 
@@ -158,7 +228,7 @@ func (h *messageHandler) handlePlay() router.RouterHandler {
 	return func(user *model.User, u *tgbotapi.Update) {
 		//chatId := user.UserID()
 		if !user.Registered {
-			h.unavailableCommand(user.UserID())
+			h.unavailableCommand(user.UserID(), user.Lang())
 			return
 		}
 
@@ -182,9 +252,9 @@ func (h *messageHandler) handlePlay() router.RouterHandler {
 			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
 		*/
 
-		message := model.PlayMessage(user)
+		message := model.PlayMessage(user, len(h.store.Review().Due(user.UserId, time.Now())))
 		user.PlayMessageHead = message
-		user.PlayMessage, _ = h.bot.Send(message.Msg)
+		user.PlayMessage, _ = h.sender.Send(message.Msg)
 	}
 }
 
@@ -192,7 +262,7 @@ func (h *messageHandler) handleReport() router.RouterHandler {
 	h.logger.Debugf("Register handler 'Report'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
-		h.unavailableCommand(user.UserID())
+		requestReport(h.sender, h.store, user, nil)
 	}
 }
 
@@ -200,13 +270,13 @@ func (h *messageHandler) handleProfile() router.RouterHandler {
 	h.logger.Debugf("Register message handler 'Profile'")
 
 	return func(user *model.User, u *tgbotapi.Update) {
-		message := model.ProfileMain(user)
+		message := profileMessage(h.store, user)
 		user.ProfileMessageHead = message
-		user.ProfileMessage, _ = h.bot.Send(message.Msg)
+		user.ProfileMessage, _ = h.sender.Send(message.Msg)
 	}
 }
 
-func (h *messageHandler) unavailableCommand(chatID int64) {
-	msg := tgbotapi.NewMessage(chatID, "Недоступная команда")
-	h.bot.Send(msg)
+func (h *messageHandler) unavailableCommand(chatID int64, lang string) {
+	msg := tgbotapi.NewMessage(chatID, locale.Get(lang, "error.unavailable_command"))
+	h.sender.Send(msg)
 }
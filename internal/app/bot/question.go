@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/qask"
+	"qask_telegram/internal/app/source"
+	"qask_telegram/internal/app/store"
+
+	"github.com/sirupsen/logrus"
+)
+
+//Question sources of the config
+const (
+	sourceQask  = "qask"
+	sourceLocal = "local"
+	sourceChain = "chain"
+)
+
+const (
+	//maxExcluded is the number of the latest seen questions sent to qask as exclusions
+	maxExcluded = 200
+	//maxSeenRetries is how many times a question is requested again if qask returns a seen one anyway
+	maxSeenRetries = 3
+)
+
+//nextQuestion gets a question the chat has not received yet and records it in the chat's history.
+//If qask keeps returning seen questions, the last one is returned rather than nothing
+func nextQuestion(ctx context.Context, questions source.QuestionSource, st store.Store, chatID int64, filter model.QuestionFilter) (*model.Question, error) {
+	filter.Exclude = st.Seen().Recent(chatID, maxExcluded)
+
+	var question *model.Question
+	for i := 0; i <= maxSeenRetries; i++ {
+		q, err := questions.GetQuestion(ctx, chatID, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		question = q
+		// Questions without an ID can not be told apart
+		if question.ID == 0 || !st.Seen().Contains(chatID, question.ID) {
+			break
+		}
+	}
+
+	if question.ID != 0 {
+		st.Seen().Add(chatID, question.ID)
+	}
+
+	return question, nil
+}
+
+//questionErrorKey is the message shown to a user when there is no question for them
+func questionErrorKey(err error) string {
+	if errors.Is(err, source.ErrNoQuestions) {
+		return "error.no_questions"
+	}
+
+	return "error.service_unavailable"
+}
+
+//configuredSource creates the question source chosen in the config.
+//In a chain qask is asked first and local packs are used when it fails, missing packs only disable the fallback
+func configuredSource(config *Config, client *qask.Client, logger *logrus.Logger) (source.QuestionSource, error) {
+	switch config.QuestionSource {
+	case sourceQask:
+		return client, nil
+	case sourceLocal:
+		return source.LoadDir(config.PacksDir, logger)
+	case sourceChain:
+		local, err := source.LoadDir(config.PacksDir, logger)
+		if err != nil {
+			logger.Warnf("Can not load question packs, there is no fallback for qask: %s", err)
+			return client, nil
+		}
+
+		return source.NewChain(logger, client, local), nil
+	}
+
+	return nil, fmt.Errorf("unknown question source \"%s\"", config.QuestionSource)
+}
@@ -0,0 +1,177 @@
+package bot
+
+import (
+	"encoding/json"
+	"net/url"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/sender"
+	"strconv"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//Telegram limits of quiz polls
+const (
+	maxPollQuestionLength    = 300
+	maxPollExplanationLength = 200
+)
+
+//sentPoll is the part of a sent poll message which tgbotapi does not decode
+type sentPoll struct {
+	Poll struct {
+		ID string `json:"id"`
+	} `json:"poll"`
+}
+
+//sendQuiz sends a multiple-choice question as a quiz poll and returns the poll message with its ID.
+//A question which does not fit the poll, is formatted or has media is sent as a message first,
+//the poll then asks only to choose an option
+func sendQuiz(s *sender.Sender, user *model.User, q *model.Question, markup tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, string, error) {
+	prefix := ""
+	if user.SessionQuestion() {
+		prefix = user.Tr("session.progress", user.Session.Number, user.Session.Length) + "\n\n"
+	}
+
+	text := prefix + q.Question
+	if q.Media != nil || q.ParseMode() != "" || utf8.RuneCountInString(text) > maxPollQuestionLength {
+		sendQuestionMessage(s, user.UserID(), q, q.Escape(prefix)+q.Question, tgbotapi.NewInlineKeyboardMarkup())
+		text = user.Tr("quiz.choose")
+	}
+
+	options, err := json.Marshal(q.Options)
+	if err != nil {
+		return tgbotapi.Message{}, "", err
+	}
+
+	replyMarkup, err := json.Marshal(markup)
+	if err != nil {
+		return tgbotapi.Message{}, "", err
+	}
+
+	params := url.Values{
+		"chat_id":           {strconv.FormatInt(user.UserID(), 10)},
+		"question":          {text},
+		"options":           {string(options)},
+		"type":              {"quiz"},
+		"correct_option_id": {strconv.Itoa(q.CorrectOption)},
+		"is_anonymous":      {"false"},
+		"reply_markup":      {string(replyMarkup)},
+	}
+
+	// A formatted explanation can not be shortened without breaking its tags, so it is left out
+	explanation := q.Comment
+	if utf8.RuneCountInString(explanation) > maxPollExplanationLength {
+		explanation = ""
+		if q.ParseMode() == "" {
+			explanation = string([]rune(q.Comment)[:maxPollExplanationLength-1]) + "…"
+		}
+	}
+	if explanation != "" {
+		params["explanation"] = []string{explanation}
+		params["explanation_parse_mode"] = []string{q.ParseMode()}
+	}
+
+	// Telegram shows the countdown of the poll itself and closes it when the time is up
+	if user.Timer > 0 {
+		params["open_period"] = []string{strconv.Itoa(user.Timer)}
+	}
+
+	raw, err := s.Request("sendPoll", params, sender.PriorityHigh)
+	if err != nil {
+		return tgbotapi.Message{}, "", err
+	}
+
+	var message tgbotapi.Message
+	if err := json.Unmarshal(raw, &message); err != nil {
+		return tgbotapi.Message{}, "", err
+	}
+
+	var poll sentPoll
+	if err := json.Unmarshal(raw, &poll); err != nil {
+		return tgbotapi.Message{}, "", err
+	}
+
+	return message, poll.Poll.ID, nil
+}
+
+//stopQuiz closes the quiz poll of the message, which reveals the correct option, and replaces its keyboard
+func stopQuiz(s *sender.Sender, chatID int64, message tgbotapi.Message, markup tgbotapi.InlineKeyboardMarkup) error {
+	replyMarkup, err := json.Marshal(markup)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{
+		"chat_id":      {strconv.FormatInt(chatID, 10)},
+		"message_id":   {strconv.Itoa(message.MessageID)},
+		"reply_markup": {string(replyMarkup)},
+	}
+
+	_, err = s.Request("stopPoll", params, sender.PriorityHigh)
+	return err
+}
+
+//acceptAnswer scores the correct answer to the current question, the answer can be a message or a quiz poll vote
+func acceptAnswer(s *sender.Sender, bot *tgbotapi.BotAPI, user *model.User) {
+	metrics.Answers.WithLabelValues("correct").Inc()
+	user.AnswerQuestion()
+	if user.HistoryEntry != nil {
+		user.HistoryEntry.Correct = true
+	}
+	finished := recordSessionResult(user, model.SessionCorrect)
+
+	points := model.QuestionPoints(user.Hints)
+	user.Points += points
+
+	text := user.Tr("answer.correct") + " " + user.Tr("answer.points", locale.Plural(user.Lang(), "points", points, points), user.Points)
+	msg := tgbotapi.NewMessage(user.UserID(), text)
+	if user.ReviewCard != nil {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(reviewGradeRow(user))
+	} else if !finished {
+		btnGetQuestion := makeButton("/getQuestion", user.Tr("question.next"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnGetQuestion))
+	}
+	s.Send(msg)
+
+	if finished {
+		sendSessionSummary(s, bot, user)
+	}
+}
+
+//handlePollAnswer scores a vote in the quiz poll of a user's current question.
+//Only one vote is possible in a quiz, so a wrong option reveals the answer
+func (b *tgbot) handlePollAnswer(answer *pollAnswer) {
+	if answer.User == nil || len(answer.OptionIDs) == 0 {
+		return
+	}
+
+	user := b.store.User().FindUser(answer.User.ID)
+	if user == nil || user.Banned {
+		return
+	}
+
+	if user.Question == nil || user.QuestionPoll != answer.PollID || !user.CloseQuestion(user.Question) {
+		b.logger.Debugf("Answer of user \"%d\" to an outdated poll \"%s\" ignored", answer.User.ID, answer.PollID)
+		return
+	}
+
+	user.Seen(answer.User)
+	b.logger.Infof("Received poll answer: user=\"%d\" option=\"%d\"", user.UserId, answer.OptionIDs[0])
+
+	if answer.OptionIDs[0] == user.Question.CorrectOption {
+		acceptAnswer(b.sender, b.bot, user)
+		return
+	}
+
+	metrics.Answers.WithLabelValues("incorrect").Inc()
+
+	finished := recordSessionResult(user, model.SessionMissed)
+	b.store.Review().AddCard(user.UserId, user.Question)
+	b.callBackQueryHandler.showAnswer(user)
+	if finished {
+		sendSessionSummary(b.sender, b.bot, user)
+	}
+}
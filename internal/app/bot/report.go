@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/store"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//requestReport asks the user to describe a problem and saves the next text message as a report
+func requestReport(s *sender.Sender, st store.Store, user *model.User, question *model.Question) {
+	user.OnText = func(text string) {
+		report := &model.Report{
+			UserID:   user.UserId,
+			Question: question,
+			Text:     text,
+		}
+
+		reply := user.Tr("report.sent")
+		if err := st.Report().CreateReport(report); err != nil {
+			reply = user.Tr("error.internal", err)
+		}
+
+		s.Send(tgbotapi.NewMessage(user.UserID(), reply))
+	}
+
+	s.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("report.describe")))
+}
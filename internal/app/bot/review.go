@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//reviewGradeRow is a row of buttons to grade the recall of a reviewed question
+func reviewGradeRow(user *model.User) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(
+		makeButton("/grade "+strconv.Itoa(int(model.ReviewForgot)), user.Tr("review.forgot")),
+		makeButton("/grade "+strconv.Itoa(int(model.ReviewHard)), user.Tr("review.hard")),
+		makeButton("/grade "+strconv.Itoa(int(model.ReviewEasy)), user.Tr("review.easy")),
+	)
+}
+
+//sendReview asks the user the most overdue question due for review
+func (h *callBackQueryHandler) sendReview(user *model.User) {
+	due := h.store.Review().Due(user.UserId, time.Now())
+	if len(due) == 0 {
+		user.ReviewCard = nil
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("review.none")))
+		return
+	}
+
+	user.ReviewCard = due[0]
+	h.askQuestion(user, due[0].Question)
+}
+
+func (h *callBackQueryHandler) handleReview() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Review'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		h.sendReview(user)
+	}
+}
+
+func (h *callBackQueryHandler) handleGrade() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Grade'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 || user.ReviewCard == nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		grade, err := strconv.Atoi(args[0])
+		if err != nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		switch model.ReviewGrade(grade) {
+		case model.ReviewForgot, model.ReviewHard, model.ReviewEasy:
+		default:
+			h.unavailableCommand(user)
+			return
+		}
+
+		user.AnswerQuestion()
+		user.ReviewCard.Grade(model.ReviewGrade(grade), time.Now())
+		h.logger.Debugf("User \"%d\" graded a review with %d, next review in %d days", user.UserId, grade, user.ReviewCard.Interval)
+
+		h.sendReview(user)
+	}
+}
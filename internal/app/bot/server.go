@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"net/http"
+	"qask_telegram/internal/app/health"
+	"qask_telegram/internal/app/metrics"
+	"time"
+)
+
+const activeUserTimeout = 15 * time.Minute
+
+func (b *tgbot) startHTTPServer() {
+	if b.config.HTTPAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", health.Handler(map[string]health.Check{
+		"updates": b.checkUpdates,
+	}))
+	// The store is kept in memory, so there is no connection to check
+	mux.Handle("/readyz", health.Handler(map[string]health.Check{
+		"updates": b.checkUpdates,
+		"qask":    b.qask.Ping,
+	}))
+
+	go func() {
+		b.logger.Infof("Starting HTTP server on \"%s\"", b.config.HTTPAddr)
+		if err := http.ListenAndServe(b.config.HTTPAddr, mux); err != nil {
+			b.logger.Errorf("HTTP server error: %s", err)
+		}
+	}()
+}
+
+func (b *tgbot) registerMetrics() {
+	metrics.RegisterGauge("active_users", "Number of users active in the last 15 minutes.", func() float64 {
+		active := 0
+		for _, user := range b.store.User().All() {
+			if time.Since(user.LastSeen) < activeUserTimeout {
+				active++
+			}
+		}
+		return float64(active)
+	})
+
+	metrics.RegisterCounter("messages_sent_total", "Number of messages sent to telegram.", func() float64 {
+		return float64(b.sender.Stats().Sent)
+	})
+	metrics.RegisterCounter("messages_retried_total", "Number of messages resent after a flood limit error.", func() float64 {
+		return float64(b.sender.Stats().Retried)
+	})
+	metrics.RegisterCounter("messages_failed_total", "Number of messages telegram failed to deliver.", func() float64 {
+		return float64(b.sender.Stats().Failed)
+	})
+	metrics.RegisterCounter("messages_dropped_total", "Number of messages dropped because the send queue was full.", func() float64 {
+		return float64(b.sender.Stats().Dropped)
+	})
+}
+
+func updateType(update *update) string {
+	switch {
+	case update.PollAnswer != nil:
+		return "poll_answer"
+	case update.Message != nil:
+		return "message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	case update.ChannelPost != nil:
+		return "channel_post"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	case update.InlineQuery != nil:
+		return "inline_query"
+	case update.ChosenInlineResult != nil:
+		return "chosen_inline_result"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//recordSessionResult counts the result of the current question if the user plays a session,
+//it reports whether the session is finished
+func recordSessionResult(user *model.User, result model.SessionResult) bool {
+	if !user.SessionQuestion() || !user.Session.Record(result) {
+		return false
+	}
+
+	return user.Session.Finished()
+}
+
+//sendSessionSummary sends the results of the user's finished session with a button to share them
+func sendSessionSummary(s *sender.Sender, bot *tgbotapi.BotAPI, user *model.User) {
+	session := user.Session
+	user.Session = nil
+
+	text := user.Tr("session.summary",
+		session.Correct,
+		session.Length,
+		session.Skipped,
+		int(session.AverageTime().Round(time.Second).Seconds()),
+		int(time.Since(session.StartedAt).Minutes()))
+
+	shareText := user.Tr("session.share", session.Correct, session.Length)
+	btnShare := tgbotapi.NewInlineKeyboardButtonURL(user.Tr("session.share_button"), shareURL(inviteLink(bot, user), shareText))
+	btnAgain := makeButton("/startSession", user.Tr("session.again"))
+
+	msg := tgbotapi.NewMessage(user.UserID(), text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnShare), tgbotapi.NewInlineKeyboardRow(btnAgain))
+	s.Send(msg)
+}
+
+func (h *callBackQueryHandler) handleStartSession() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'StartSession'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		user.StopTimer()
+		user.Session = model.NewSession(model.SessionLength)
+		h.logger.Infof("User \"%d\" started a session of %d questions", user.UserId, user.Session.Length)
+
+		h.sendQuestion(user)
+	}
+}
+
+func (h *callBackQueryHandler) handleSkipQuestion() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SkipQuestion'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		if !user.SessionQuestion() || !user.CloseQuestion(user.Question) {
+			return
+		}
+
+		finished := recordSessionResult(user, model.SessionSkipped)
+		h.store.Review().AddCard(user.UserId, user.Question)
+		h.showAnswer(user)
+
+		if finished {
+			sendSessionSummary(h.sender, h.bot, user)
+			return
+		}
+
+		h.sendQuestion(user)
+	}
+}
+
+func (h *callBackQueryHandler) handlePauseSession() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'PauseSession'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		if !user.SessionQuestion() {
+			return
+		}
+
+		user.Session.Pause()
+		// Answers are not accepted until the session is resumed
+		user.AnswerQuestion()
+
+		text := user.Tr("session.paused", user.Session.Played(), user.Session.Length)
+		markup := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/resumeSession", user.Tr("session.resume"))))
+
+		// A quiz poll can not be edited and stopping it would reveal the answer, so it is deleted
+		if user.QuestionPoll != "" {
+			h.sender.Send(tgbotapi.NewDeleteMessage(user.UserID(), user.QuestionMessage.MessageID))
+			msg := tgbotapi.NewMessage(user.UserID(), text)
+			msg.ReplyMarkup = markup
+			h.sender.Send(msg)
+			return
+		}
+
+		h.sender.Send(editQuestionMessage(user.UserID(), user.QuestionMessage, text, "", markup))
+	}
+}
+
+func (h *callBackQueryHandler) handleResumeSession() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'ResumeSession'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		if user.Session == nil || !user.Session.Paused {
+			h.unavailableCommand(user)
+			return
+		}
+
+		user.Session.Resume()
+		if user.Session.Question == nil {
+			h.sendQuestion(user)
+			return
+		}
+
+		h.askQuestion(user, user.Session.Question)
+	}
+}
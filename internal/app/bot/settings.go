@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//updateSettings replaces the current settings screen with the changed one.
+//The main settings message we return to shows the changed value too, so it is rebuilt
+func (h *callBackQueryHandler) updateSettings(user *model.User, message *model.Message) {
+	settings := model.GameMainSettingsMessage(user)
+	if user.PlayMessageHead != nil && user.PlayMessageHead.Prev != nil {
+		settings.Prev = user.PlayMessageHead.Prev.Prev
+	}
+
+	message.Prev = settings
+	user.PlayMessageHead = message
+	h.sender.Send(message.Msg)
+}
+
+func (h *callBackQueryHandler) handleCategories() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Categories'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		message := model.GameCategoriesSettingsMessage(user, h.categories)
+		message.Prev = user.PlayMessageHead
+		user.PlayMessageHead = message
+		h.sender.Send(message.Msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleToggleCategory() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'ToggleCategory'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 {
+			h.unavailableCommand(user)
+			return
+		}
+
+		if args[0] == "all" {
+			user.Categories = nil
+		} else {
+			i, err := strconv.Atoi(args[0])
+			if err != nil || i < 0 || i >= len(h.categories) {
+				h.unavailableCommand(user)
+				return
+			}
+
+			user.ToggleCategory(h.categories[i])
+		}
+		h.questions.Invalidate(user.UserID())
+
+		h.updateSettings(user, model.GameCategoriesSettingsMessage(user, h.categories))
+	}
+}
+
+func (h *callBackQueryHandler) handleDifficulty() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Difficulty'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		message := model.GameDifficultySettingsMessage(user)
+		message.Prev = user.PlayMessageHead
+		user.PlayMessageHead = message
+		h.sender.Send(message.Msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleSetDifficulty() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SetDifficulty'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 {
+			h.unavailableCommand(user)
+			return
+		}
+
+		difficulty, err := strconv.Atoi(args[0])
+		if err != nil || difficulty < model.DifficultyAny || difficulty > model.DifficultyHard {
+			h.unavailableCommand(user)
+			return
+		}
+
+		user.Difficulty = difficulty
+		h.questions.Invalidate(user.UserID())
+		h.updateSettings(user, model.GameDifficultySettingsMessage(user))
+	}
+}
+
+func (h *callBackQueryHandler) handleResetHistory() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'ResetHistory'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		count := h.store.Seen().Reset(user.UserID())
+		h.questions.Invalidate(user.UserID())
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("history.reset", locale.Plural(user.Lang(), "questions", count, count)))
+		h.sender.Send(msg)
+	}
+}
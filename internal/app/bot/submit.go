@@ -0,0 +1,349 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/store"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//submitFields are the fields of a submitted question in the order they are asked
+var submitFields = []string{"question", "answer", "comment", "source"}
+
+//skipField is the reply skipping an optional field
+const skipField = "-"
+
+//askSubmitField asks the user for a field of the draft and then for the next one,
+//the draft is shown for confirmation after the last field
+func askSubmitField(s *sender.Sender, user *model.User, i int) {
+	field := submitFields[i]
+	min, max := model.SubmissionFieldLength(field)
+
+	user.OnText = func(text string) {
+		draft := user.SubmissionDraft
+		if draft == nil {
+			return
+		}
+
+		text = strings.TrimSpace(text)
+		if min == 0 && text == skipField {
+			text = ""
+		}
+
+		if !model.ValidSubmissionField(field, text) {
+			s.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.invalid", min, max)))
+			askSubmitField(s, user, i)
+			return
+		}
+
+		draft.SetField(field, text)
+		if i+1 < len(submitFields) {
+			askSubmitField(s, user, i+1)
+			return
+		}
+
+		btnSend := makeButton("/submitSend", user.Tr("submit.send"))
+		btnCancel := makeButton("/submitCancel", user.Tr("submit.cancel"))
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("submit.preview")+"\n\n"+submissionText(user, draft))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnSend, btnCancel))
+		s.Send(msg)
+	}
+
+	text := user.Tr("submit.enter." + field)
+	if min == 0 {
+		text += "\n" + user.Tr("submit.skip", skipField)
+	}
+	s.Send(tgbotapi.NewMessage(user.UserID(), text))
+}
+
+//submissionText lists the fields of a submitted question, empty optional fields are shown as a dash
+func submissionText(user *model.User, q *model.Question) string {
+	comment, source := q.Comment, q.Source
+	if comment == "" {
+		comment = skipField
+	}
+	if source == "" {
+		source = skipField
+	}
+
+	return user.Tr("submit.fields", q.Question, q.Answer, comment, source)
+}
+
+//moderationMessage is a card of a pending submission for the admin with buttons to approve, edit or reject it
+func moderationMessage(admin *model.User, st store.Store, submission *model.Submission) tgbotapi.MessageConfig {
+	author := strconv.Itoa(submission.UserID)
+	if user := st.User().FindUser(submission.UserID); user != nil {
+		author = fmt.Sprintf("%s (%d)", user.FirstName, user.UserId)
+	}
+
+	text := admin.Tr("submit.card", submission.ID, author) + "\n\n" + submissionText(admin, submission.Question)
+
+	id := strconv.Itoa(submission.ID)
+	btnApprove := makeButton("/approveSubmission "+id, admin.Tr("submit.approve"))
+	btnEdit := makeButton("/editSubmission "+id, admin.Tr("submit.edit"))
+	btnReject := makeButton("/rejectSubmission "+id, admin.Tr("submit.reject"))
+
+	msg := tgbotapi.NewMessage(admin.UserID(), text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnApprove, btnEdit, btnReject))
+	return msg
+}
+
+func (h *messageHandler) handleSubmit() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Submit'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		user.SubmissionDraft = &model.Question{
+			Author: user.FirstName,
+		}
+
+		h.sendText(user.UserID(), user.Tr("submit.start"))
+		askSubmitField(h.sender, user, 0)
+	}
+}
+
+func (h *messageHandler) handleAdminSubmissions() router.RouterHandler {
+	h.logger.Debugf("Register admin handler 'Submissions'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		pending := h.store.Submission().Pending()
+		if len(pending) == 0 {
+			h.sendText(user.UserID(), user.Tr("submit.none"))
+			return
+		}
+
+		if len(pending) > adminRecentLimit {
+			pending = pending[:adminRecentLimit]
+		}
+
+		for _, submission := range pending {
+			h.sender.Send(moderationMessage(user, h.store, submission))
+		}
+	}
+}
+
+func (h *callBackQueryHandler) handleSubmitSend() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SubmitSend'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if user.SubmissionDraft == nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		submission := &model.Submission{
+			UserID:   user.UserId,
+			Question: user.SubmissionDraft,
+		}
+		if err := h.store.Submission().CreateSubmission(submission); err != nil {
+			h.internalError(user, err)
+			return
+		}
+		user.SubmissionDraft = nil
+
+		h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, user.Tr("submit.sent")))
+
+		for _, id := range h.admins.all() {
+			if admin := h.store.User().FindUser(id); admin != nil {
+				pushTo(h.sender, admin, moderationMessage(admin, h.store, submission))
+			}
+		}
+	}
+}
+
+func (h *callBackQueryHandler) handleSubmitCancel() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SubmitCancel'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		user.SubmissionDraft = nil
+		user.OnText = nil
+
+		h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, user.Tr("submit.cancelled")))
+	}
+}
+
+//pendingSubmission returns the submission of the callback's argument if it still waits for moderation
+func (h *callBackQueryHandler) pendingSubmission(user *model.User, u *tgbotapi.Update) *model.Submission {
+	_, args := router.ParseCommand(u.CallbackQuery.Data)
+	if len(args) == 0 {
+		h.unavailableCommand(user)
+		return nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		h.unavailableCommand(user)
+		return nil
+	}
+
+	submission := h.store.Submission().FindSubmission(id)
+	if submission == nil {
+		h.unavailableCommand(user)
+		return nil
+	}
+
+	if submission.Status != model.SubmissionPending {
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.already_moderated", submission.ID)))
+		return nil
+	}
+
+	return submission
+}
+
+//notifyAuthor lets the author of the submission know the result of moderation
+func (h *callBackQueryHandler) notifyAuthor(submission *model.Submission, key string, args ...interface{}) {
+	author := h.store.User().FindUser(submission.UserID)
+	if author == nil {
+		return
+	}
+
+	args = append([]interface{}{submission.Question.Question}, args...)
+	pushTo(h.sender, author, tgbotapi.NewMessage(author.UserID(), author.Tr(key, args...)))
+}
+
+func (h *callBackQueryHandler) handleApproveSubmission() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'ApproveSubmission'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		submission := h.pendingSubmission(user, u)
+		if submission == nil {
+			return
+		}
+
+		// The submission is claimed before publishing, so it is never published twice
+		if !h.store.Submission().Moderate(submission.ID, user.UserId, model.SubmissionPending, model.SubmissionPublishing, "") {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.already_moderated", submission.ID)))
+			return
+		}
+
+		id, err := h.qask.AddQuestion(context.Background(), int64(submission.UserID), submission.Question)
+		if err != nil {
+			h.logger.Errorf("Can not publish submission \"%d\": %s", submission.ID, err)
+			h.store.Submission().Moderate(submission.ID, user.UserId, model.SubmissionPublishing, model.SubmissionPending, "")
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.approve_failed", err)))
+			return
+		}
+
+		submission.Question.ID = id
+		h.store.Submission().Moderate(submission.ID, user.UserId, model.SubmissionPublishing, model.SubmissionApproved, "")
+
+		text := u.CallbackQuery.Message.Text + "\n\n" + user.Tr("submit.approved_by", user.FirstName)
+		h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), u.CallbackQuery.Message.MessageID, text))
+		h.notifyAuthor(submission, "submit.approved")
+	}
+}
+
+func (h *callBackQueryHandler) handleRejectSubmission() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'RejectSubmission'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		submission := h.pendingSubmission(user, u)
+		if submission == nil {
+			return
+		}
+
+		message := *u.CallbackQuery.Message
+		user.OnText = func(reason string) {
+			if !h.store.Submission().Moderate(submission.ID, user.UserId, model.SubmissionPending, model.SubmissionRejected, reason) {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.already_moderated", submission.ID)))
+				return
+			}
+
+			text := message.Text + "\n\n" + user.Tr("submit.rejected_by", user.FirstName, reason)
+			h.sender.Send(tgbotapi.NewEditMessageText(user.UserID(), message.MessageID, text))
+			h.notifyAuthor(submission, "submit.rejected", reason)
+		}
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.enter_reason", submission.ID)))
+	}
+}
+
+func (h *callBackQueryHandler) handleEditSubmission() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'EditSubmission'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		submission := h.pendingSubmission(user, u)
+		if submission == nil {
+			return
+		}
+
+		var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+		for _, field := range submitFields {
+			btnField := makeButton(fmt.Sprintf("/editSubmissionField %d %s", submission.ID, field), user.Tr("submit.field."+field))
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnField))
+		}
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("submit.choose_field", submission.ID))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		h.sender.Send(msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleEditSubmissionField() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'EditSubmissionField'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 2 || !isSubmitField(args[1]) {
+			h.unavailableCommand(user)
+			return
+		}
+
+		submission := h.pendingSubmission(user, u)
+		if submission == nil {
+			return
+		}
+
+		h.askSubmissionEdit(user, submission, args[1])
+	}
+}
+
+//askSubmissionEdit asks an admin for a new text of a field of a pending submission until the text is valid
+func (h *callBackQueryHandler) askSubmissionEdit(user *model.User, submission *model.Submission, field string) {
+	min, max := model.SubmissionFieldLength(field)
+	user.OnText = func(text string) {
+		// Another admin may have moderated the submission while the text was typed
+		if submission.Status != model.SubmissionPending {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.already_moderated", submission.ID)))
+			return
+		}
+
+		text = strings.TrimSpace(text)
+		if min == 0 && text == skipField {
+			text = ""
+		}
+
+		if !model.ValidSubmissionField(field, text) {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.invalid", min, max)))
+			h.askSubmissionEdit(user, submission, field)
+			return
+		}
+
+		// The status is checked again with the lock held, the submission can be approved just now
+		if !h.store.Submission().EditField(submission.ID, field, text) {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("submit.already_moderated", submission.ID)))
+			return
+		}
+
+		h.sender.Send(moderationMessage(user, h.store, submission))
+	}
+
+	text := user.Tr("submit.enter."+field) + "\n\n" + user.Tr("submit.current", submission.Question.Field(field))
+	h.sender.Send(tgbotapi.NewMessage(user.UserID(), text))
+}
+
+func isSubmitField(field string) bool {
+	for _, f := range submitFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
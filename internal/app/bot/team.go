@@ -0,0 +1,402 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/store"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const teamLeaderboardLimit = 10
+
+//userTeam returns the team of the user or nil if the user is not a member of any
+func userTeam(st store.Store, user *model.User) *model.Team {
+	if user.TeamID == 0 {
+		return nil
+	}
+
+	team := st.Team().FindTeam(user.TeamID)
+	if team == nil || !team.IsMember(user.UserId) {
+		user.TeamID = 0
+		return nil
+	}
+
+	return team
+}
+
+//teamMembers returns the members of the team who are users of the bot
+func teamMembers(st store.Store, team *model.Team) []*model.User {
+	ids := team.MemberIDs()
+	members := make([]*model.User, 0, len(ids))
+	for _, id := range ids {
+		if member := st.User().FindUser(id); member != nil {
+			members = append(members, member)
+		}
+	}
+
+	return members
+}
+
+//teamMenu is the team of the user with its stats or, if the user has no team, the ways to get one
+func teamMenu(st store.Store, user *model.User) tgbotapi.MessageConfig {
+	team := userTeam(st, user)
+	if team == nil {
+		btnCreate := makeButton("/createTeam", user.Tr("team.create"))
+		btnJoin := makeButton("/joinTeam", user.Tr("team.join"))
+		btnLeaderboard := makeButton("/teamLeaderboard", user.Tr("team.leaderboard"))
+
+		msg := tgbotapi.NewMessage(user.UserID(), user.Tr("team.none"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(btnCreate, btnJoin),
+			tgbotapi.NewInlineKeyboardRow(btnLeaderboard))
+		return msg
+	}
+
+	captain := ""
+	members := teamMembers(st, team)
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.FirstName)
+		if team.IsCaptain(member.UserId) {
+			captain = member.FirstName
+		}
+	}
+
+	stats := st.TeamResult().Stats(team.ID)
+	text := user.Tr("team.info", team.Name, captain, len(names), strings.Join(names, ", "), team.Code) + "\n\n" +
+		user.Tr("team.stats", stats.Played, stats.Correct, locale.Plural(user.Lang(), "points", stats.Points, stats.Points))
+
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+	if team.IsCaptain(user.UserId) {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(makeButton("/teamQuestion", user.Tr("team.question_button"))))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(makeButton("/teamLeaderboard", user.Tr("team.leaderboard"))))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(makeButton("/leaveTeam", user.Tr("team.leave"))))
+
+	msg := tgbotapi.NewMessage(user.UserID(), text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msg
+}
+
+//notifyTeam sends every member of the team a message made in the member's language, a nil message skips the member
+func notifyTeam(s *sender.Sender, st store.Store, team *model.Team, message func(member *model.User) tgbotapi.Chattable) {
+	for _, member := range teamMembers(st, team) {
+		if msg := message(member); msg != nil {
+			pushTo(s, member, msg)
+		}
+	}
+}
+
+//profileMessage is the profile of the user with the number of invited friends and the team
+func profileMessage(st store.Store, user *model.User) *model.Message {
+	team := ""
+	if t := userTeam(st, user); t != nil {
+		team = t.Name
+	}
+
+	return model.ProfileMain(user, len(st.User().Referrals(user.UserId)), team)
+}
+
+func (h *messageHandler) handleTeam() router.RouterHandler {
+	h.logger.Debugf("Register message handler 'Team'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		h.sender.Send(teamMenu(h.store, user))
+	}
+}
+
+func (h *callBackQueryHandler) handleTeam() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Team'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		h.sender.Send(teamMenu(h.store, user))
+	}
+}
+
+func (h *callBackQueryHandler) handleCreateTeam() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'CreateTeam'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if userTeam(h.store, user) != nil {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.already_member")))
+			return
+		}
+
+		user.OnText = func(name string) {
+			name = strings.TrimSpace(name)
+			if length := utf8.RuneCountInString(name); length < model.MinTeamName || length > model.MaxTeamName {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.invalid_name", model.MinTeamName, model.MaxTeamName)))
+				return
+			}
+
+			if userTeam(h.store, user) != nil {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.already_member")))
+				return
+			}
+
+			team := h.store.Team().CreateTeam(name, user.UserId)
+			user.TeamID = team.ID
+
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.created", team.Name, team.Code)))
+			h.sender.Send(teamMenu(h.store, user))
+		}
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.enter_name")))
+	}
+}
+
+func (h *callBackQueryHandler) handleJoinTeam() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'JoinTeam'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		if userTeam(h.store, user) != nil {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.already_member")))
+			return
+		}
+
+		user.OnText = func(code string) {
+			team := h.store.Team().FindTeamByCode(code)
+			if team == nil {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.not_found")))
+				return
+			}
+
+			if userTeam(h.store, user) != nil {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.already_member")))
+				return
+			}
+
+			if !team.AddMember(user.UserId) {
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.full", model.MaxTeamMembers)))
+				return
+			}
+			user.TeamID = team.ID
+
+			notifyTeam(h.sender, h.store, team, func(member *model.User) tgbotapi.Chattable {
+				if member.UserId == user.UserId {
+					return nil
+				}
+				return tgbotapi.NewMessage(member.UserID(), member.Tr("team.member_joined", user.FirstName))
+			})
+			h.logger.Infof("User \"%d\" joined team \"%d\"", user.UserId, team.ID)
+
+			h.sender.Send(teamMenu(h.store, user))
+		}
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.enter_code")))
+	}
+}
+
+func (h *callBackQueryHandler) handleLeaveTeam() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'LeaveTeam'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		team := userTeam(h.store, user)
+		if team == nil {
+			h.unavailableCommand(user)
+			return
+		}
+
+		wasCaptain := team.IsCaptain(user.UserId)
+		user.TeamID = 0
+		if team.RemoveMember(user.UserId) {
+			h.store.Team().RemoveTeam(team.ID)
+		} else {
+			notifyTeam(h.sender, h.store, team, func(member *model.User) tgbotapi.Chattable {
+				text := member.Tr("team.member_left", user.FirstName)
+				if wasCaptain && team.IsCaptain(member.UserId) {
+					text += "\n" + member.Tr("team.new_captain")
+				}
+				return tgbotapi.NewMessage(member.UserID(), text)
+			})
+		}
+		h.logger.Infof("User \"%d\" left team \"%d\"", user.UserId, team.ID)
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.left", team.Name)))
+	}
+}
+
+//captainTeam returns the team of the user if the user is its captain
+func (h *callBackQueryHandler) captainTeam(user *model.User) *model.Team {
+	team := userTeam(h.store, user)
+	if team == nil {
+		h.unavailableCommand(user)
+		return nil
+	}
+
+	if !team.IsCaptain(user.UserId) {
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.captain_only")))
+		return nil
+	}
+
+	return team
+}
+
+func (h *callBackQueryHandler) handleTeamQuestion() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'TeamQuestion'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		team := h.captainTeam(user)
+		if team == nil {
+			return
+		}
+
+		// The questions follow the captain's settings and are not repeated to the captain
+		question, err := nextQuestion(context.Background(), h.questions, h.store, user.UserID(), user.QuestionFilter())
+		if err != nil {
+			h.logger.Errorf("Can not get question for team \"%d\": %s", team.ID, err)
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr(questionErrorKey(err))))
+			return
+		}
+
+		team.SetQuestion(question)
+		metrics.QuestionsServed.Inc()
+		h.logger.Infof("Team \"%d\" got question \"%d\"", team.ID, question.ID)
+
+		for _, member := range teamMembers(h.store, team) {
+			text := question.Escape(member.Tr("team.question", team.Name)) + "\n\n" + question.Question
+
+			markup := tgbotapi.NewInlineKeyboardMarkup()
+			if team.IsCaptain(member.UserId) {
+				btnAnswer := makeButton("/teamAnswer", member.Tr("team.answer_button"))
+				btnShow := makeButton("/teamShowAnswer", member.Tr("question.show_answer"))
+				markup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(btnAnswer, btnShow))
+			} else {
+				text += "\n\n" + question.Escape(member.Tr("team.discuss"))
+			}
+
+			sendQuestionMessage(h.sender, member.UserID(), question, text, markup)
+		}
+	}
+}
+
+//closeTeamQuestion records the result of the team's question and reveals the answer to the members
+func (h *callBackQueryHandler) closeTeamQuestion(team *model.Team, question *model.Question, captain *model.User, correct bool) {
+	if !team.CloseQuestion(question) {
+		return
+	}
+
+	result := &model.TeamResult{
+		TeamID:     team.ID,
+		QuestionID: question.ID,
+		Correct:    correct,
+		AnsweredAt: time.Now(),
+	}
+	if correct {
+		result.Points = model.MaxPoints
+	}
+	h.store.TeamResult().AddResult(result)
+
+	key := "team.answer"
+	if correct {
+		key = "team.correct"
+	}
+
+	notifyTeam(h.sender, h.store, team, func(member *model.User) tgbotapi.Chattable {
+		text := member.Tr(key, question.Escape(captain.FirstName), question.Answer)
+		if question.Comment != "" {
+			text += "\n\n" + question.Comment
+		}
+
+		msg := tgbotapi.NewMessage(member.UserID(), text)
+		msg.ParseMode = question.ParseMode()
+		if team.IsCaptain(member.UserId) {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(makeButton("/teamQuestion", member.Tr("question.next"))))
+		}
+		return msg
+	})
+}
+
+func (h *callBackQueryHandler) handleTeamAnswer() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'TeamAnswer'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		team := h.captainTeam(user)
+		if team == nil {
+			return
+		}
+
+		question := team.OpenQuestion()
+		if question == nil {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.no_question")))
+			return
+		}
+
+		user.OnText = func(answer string) {
+			if team.OpenQuestion() != question || !team.IsCaptain(user.UserId) {
+				return
+			}
+
+			if !question.CheckAnswer(answer) {
+				metrics.Answers.WithLabelValues("incorrect").Inc()
+
+				msg := tgbotapi.NewMessage(user.UserID(), user.Tr("team.incorrect"))
+				msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+					makeButton("/teamAnswer", user.Tr("team.answer_button")),
+					makeButton("/teamShowAnswer", user.Tr("question.show_answer"))))
+				h.sender.Send(msg)
+				return
+			}
+
+			metrics.Answers.WithLabelValues("correct").Inc()
+			h.logger.Infof("Team \"%d\" answered question \"%d\" correctly", team.ID, question.ID)
+			h.closeTeamQuestion(team, question, user, true)
+		}
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.enter_answer")))
+	}
+}
+
+func (h *callBackQueryHandler) handleTeamShowAnswer() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'TeamShowAnswer'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		team := h.captainTeam(user)
+		if team == nil {
+			return
+		}
+
+		question := team.OpenQuestion()
+		if question == nil {
+			h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("team.no_question")))
+			return
+		}
+
+		user.OnText = nil
+		h.closeTeamQuestion(team, question, user, false)
+	}
+}
+
+func (h *callBackQueryHandler) handleTeamLeaderboard() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'TeamLeaderboard'")
+
+	return func(user *model.User, u *tgbotapi.Update) {
+		lines := make([]string, 0, teamLeaderboardLimit)
+		for _, stats := range h.store.TeamResult().Top(teamLeaderboardLimit) {
+			// Results of disbanded teams are kept, but the teams are not shown
+			team := h.store.Team().FindTeam(stats.TeamID)
+			if team == nil {
+				continue
+			}
+
+			points := locale.Plural(user.Lang(), "points", stats.Points, stats.Points)
+			lines = append(lines, fmt.Sprintf("%d. %s — %s (%d/%d)", len(lines)+1, team.Name, points, stats.Correct, stats.Played))
+		}
+
+		text := user.Tr("team.leaderboard_empty")
+		if len(lines) != 0 {
+			text = user.Tr("team.leaderboard_title") + "\n" + strings.Join(lines, "\n")
+		}
+
+		h.sender.Send(tgbotapi.NewMessage(user.UserID(), text))
+	}
+}
@@ -0,0 +1,154 @@
+package bot
+
+import (
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/router"
+	"strconv"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//countdownInterval is how often the question message is edited to show the remaining time
+const countdownInterval = 10 * time.Second
+
+//questionText is the current question with the remaining time if the question is timed
+func questionText(user *model.User) string {
+	q := user.Question
+	text := q.Question
+	if user.SessionQuestion() {
+		text = q.Escape(user.Tr("session.progress", user.Session.Number, user.Session.Length)) + "\n\n" + text
+	}
+
+	if user.QuestionDeadline.IsZero() {
+		return text
+	}
+
+	left := time.Until(user.QuestionDeadline).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+
+	return text + "\n\n" + q.Escape(user.Tr("timer.left", int(left.Seconds())))
+}
+
+func questionMarkup(user *model.User) tgbotapi.InlineKeyboardMarkup {
+	var rows = make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	btnHint := makeButton("/hint", user.Tr("question.hint"))
+	btnAnswer := makeButton("/showAnswer", user.Tr("question.show_answer"))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnHint, btnAnswer))
+
+	if user.SessionQuestion() {
+		btnSkip := makeButton("/skipQuestion", user.Tr("session.skip"))
+		btnPause := makeButton("/pauseSession", user.Tr("session.pause"))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnSkip, btnPause))
+	}
+
+	btnFavorite := makeButton("/favorite", user.Tr("favorites.add"))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnFavorite))
+
+	btnReport := makeButton("/sendReport", user.Tr("question.report"))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(btnReport))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+//startCountdown edits the question message with the remaining time until the question is answered,
+//when the time runs out the answer is revealed
+func (h *callBackQueryHandler) startCountdown(user *model.User, question *model.Question, message tgbotapi.Message) {
+	stop := make(chan struct{})
+	once := &sync.Once{}
+	user.StartTimer(func() {
+		once.Do(func() { close(stop) })
+	})
+
+	deadline := user.QuestionDeadline
+	go func() {
+		ticker := time.NewTicker(countdownInterval)
+		defer ticker.Stop()
+
+		timeout := time.NewTimer(time.Until(deadline))
+		defer timeout.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				// The edit is queued with the lock held and messages to a chat are sent in order,
+				// so it can not overwrite the answer revealed meanwhile
+				open := user.IfQuestionOpen(question, func() {
+					// A quiz poll shows the countdown itself
+					if user.QuestionPoll != "" {
+						return
+					}
+
+					msg := editQuestionMessage(user.UserID(), message, questionText(user), question.ParseMode(), questionMarkup(user))
+					h.sender.Push(msg)
+				})
+				if !open {
+					return
+				}
+			case <-timeout.C:
+				if !user.CloseQuestion(question) {
+					return
+				}
+
+				h.logger.Infof("Time is up for user \"%d\"", user.UserId)
+				metrics.Answers.WithLabelValues("expired").Inc()
+
+				finished := recordSessionResult(user, model.SessionMissed)
+				h.store.Review().AddCard(user.UserId, question)
+				h.showAnswer(user)
+				h.sender.Send(tgbotapi.NewMessage(user.UserID(), user.Tr("timer.expired")))
+				if finished {
+					sendSessionSummary(h.sender, h.bot, user)
+				}
+				return
+			}
+		}
+	}()
+}
+
+func (h *callBackQueryHandler) handleTimer() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'Timer'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		message := model.GameTimerSettingsMessage(user)
+		message.Prev = user.PlayMessageHead
+		user.PlayMessageHead = message
+		h.sender.Send(message.Msg)
+	}
+}
+
+func (h *callBackQueryHandler) handleSetTimer() router.RouterHandler {
+	h.logger.Debugf("Register callback handler 'SetTimer'")
+	return func(user *model.User, u *tgbotapi.Update) {
+		_, args := router.ParseCommand(u.CallbackQuery.Data)
+		if len(args) != 1 {
+			h.unavailableCommand(user)
+			return
+		}
+
+		seconds, err := strconv.Atoi(args[0])
+		if err != nil || !validTimer(seconds) {
+			h.unavailableCommand(user)
+			return
+		}
+
+		user.Timer = seconds
+		h.updateSettings(user, model.GameTimerSettingsMessage(user))
+	}
+}
+
+func validTimer(seconds int) bool {
+	for _, option := range model.TimerOptions {
+		if seconds == option {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const (
+	pollTimeout  = 60
+	retryTimeout = 3 * time.Second
+	// getUpdates returns after pollTimeout at the latest, so missing several polls means the loop is stuck
+	updatesStuckTimeout = 3 * pollTimeout * time.Second
+)
+
+//update is a telegram update extended with the types tgbotapi does not know about
+type update struct {
+	tgbotapi.Update
+	PollAnswer *pollAnswer `json:"poll_answer"`
+}
+
+//pollAnswer is an answer of a user in a non-anonymous poll sent by the bot
+type pollAnswer struct {
+	PollID    string         `json:"poll_id"`
+	User      *tgbotapi.User `json:"user"`
+	OptionIDs []int          `json:"option_ids"`
+}
+
+type updatesChannel <-chan update
+
+//pollUpdates long polls telegram for updates and records the time of every successful request
+func (b *tgbot) pollUpdates() updatesChannel {
+	ch := make(chan update, b.bot.Buffer)
+	offset := 0
+
+	atomic.StoreInt64(&b.lastPoll, time.Now().UnixNano())
+
+	go func() {
+		for {
+			updates, err := b.getUpdates(offset)
+			if err != nil {
+				b.logger.Errorf("Failed to get updates, retrying in %s: %s", retryTimeout, err)
+				time.Sleep(retryTimeout)
+				continue
+			}
+
+			atomic.StoreInt64(&b.lastPoll, time.Now().UnixNano())
+
+			for _, update := range updates {
+				if update.UpdateID >= offset {
+					offset = update.UpdateID + 1
+					ch <- update
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+//getUpdates requests updates directly since tgbotapi.GetUpdates drops poll answers
+func (b *tgbot) getUpdates(offset int) ([]update, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.Itoa(offset))
+	params.Set("timeout", strconv.Itoa(pollTimeout))
+
+	resp, err := b.bot.MakeRequest("getUpdates", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var updates []update
+	if err := json.Unmarshal(resp.Result, &updates); err != nil {
+		return nil, err
+	}
+
+	return updates, nil
+}
+
+func (b *tgbot) checkUpdates() error {
+	since := time.Since(time.Unix(0, atomic.LoadInt64(&b.lastPoll)))
+	if since > updatesStuckTimeout {
+		return fmt.Errorf("no updates received for %s", since.Round(time.Second))
+	}
+
+	return nil
+}
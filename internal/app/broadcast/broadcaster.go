@@ -0,0 +1,217 @@
+package broadcast
+
+import (
+	"errors"
+	"qask_telegram/internal/app/model"
+	"qask_telegram/internal/app/sender"
+	"qask_telegram/internal/app/store"
+	"sync"
+	"sync/atomic"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/sirupsen/logrus"
+)
+
+const workers = 8
+
+var (
+	//ErrNoDraft is returned when an admin has no broadcast to start or cancel
+	ErrNoDraft = errors.New("there is no broadcast draft")
+	//ErrAlreadyRunning is returned when an admin's broadcast is being sent
+	ErrAlreadyRunning = errors.New("broadcast is already running")
+)
+
+//Result is a delivery report of a broadcast
+type Result struct {
+	Total     int
+	Delivered int
+	Blocked   int
+	Failed    int
+	Cancelled bool
+}
+
+//Broadcast is a message to many users composed by an admin
+type Broadcast struct {
+	AuthorID  int
+	Text      string
+	Filter    Filter
+	running   bool
+	cancel    chan struct{}
+	delivered int64
+	blocked   int64
+	failed    int64
+}
+
+//Broadcaster keeps broadcasts of admins, one per admin
+type Broadcaster struct {
+	mu         sync.Mutex
+	sender     *sender.Sender
+	store      store.Store
+	logger     *logrus.Logger
+	broadcasts map[int]*Broadcast
+}
+
+//New creates a broadcaster
+func New(sender *sender.Sender, store store.Store, logger *logrus.Logger) *Broadcaster {
+	return &Broadcaster{
+		sender:     sender,
+		store:      store,
+		logger:     logger,
+		broadcasts: make(map[int]*Broadcast),
+	}
+}
+
+//Draft creates a broadcast to all users replacing the author's previous draft
+func (b *Broadcaster) Draft(authorID int, text string) (*Broadcast, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if br, ok := b.broadcasts[authorID]; ok && br.running {
+		return nil, ErrAlreadyRunning
+	}
+
+	br := &Broadcast{
+		AuthorID: authorID,
+		Text:     text,
+		Filter:   FilterAll,
+	}
+	b.broadcasts[authorID] = br
+
+	return br, nil
+}
+
+//SetFilter changes recipients of the author's draft
+func (b *Broadcaster) SetFilter(authorID int, filter Filter) (*Broadcast, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.broadcasts[authorID]
+	if !ok {
+		return nil, ErrNoDraft
+	}
+
+	if br.running {
+		return nil, ErrAlreadyRunning
+	}
+
+	br.Filter = filter
+	return br, nil
+}
+
+//Recipients returns users matching the filter
+func (b *Broadcaster) Recipients(filter Filter) []*model.User {
+	recipients := make([]*model.User, 0)
+	for _, user := range b.store.User().All() {
+		if filter.Match(user) {
+			recipients = append(recipients, user)
+		}
+	}
+
+	return recipients
+}
+
+//Start sends the author's draft in background through the low priority queue,
+//onDone is called with the delivery report when all messages are sent or the broadcast is cancelled
+func (b *Broadcaster) Start(authorID int, onDone func(Result)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.broadcasts[authorID]
+	if !ok {
+		return ErrNoDraft
+	}
+
+	if br.running {
+		return ErrAlreadyRunning
+	}
+
+	br.running = true
+	br.cancel = make(chan struct{})
+
+	recipients := b.Recipients(br.Filter)
+	b.logger.Infof("Broadcast by admin \"%d\" started: filter=\"%s\" recipients=\"%d\"", authorID, br.Filter, len(recipients))
+
+	go b.run(br, recipients, onDone)
+
+	return nil
+}
+
+//Cancel stops the author's running broadcast or discards the draft,
+//it reports whether the broadcast was running
+func (b *Broadcaster) Cancel(authorID int) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br, ok := b.broadcasts[authorID]
+	if !ok {
+		return false, ErrNoDraft
+	}
+
+	if br.running {
+		close(br.cancel)
+	}
+	delete(b.broadcasts, authorID)
+
+	return br.running, nil
+}
+
+func (b *Broadcaster) run(br *Broadcast, recipients []*model.User, onDone func(Result)) {
+	users := make(chan *model.User)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for user := range users {
+				b.send(br, user)
+			}
+		}()
+	}
+
+	cancelled := false
+	for _, user := range recipients {
+		select {
+		case <-br.cancel:
+			cancelled = true
+		case users <- user:
+		}
+
+		if cancelled {
+			break
+		}
+	}
+	close(users)
+	wg.Wait()
+
+	b.mu.Lock()
+	if b.broadcasts[br.AuthorID] == br {
+		delete(b.broadcasts, br.AuthorID)
+	}
+	b.mu.Unlock()
+
+	result := Result{
+		Total:     len(recipients),
+		Delivered: int(atomic.LoadInt64(&br.delivered)),
+		Blocked:   int(atomic.LoadInt64(&br.blocked)),
+		Failed:    int(atomic.LoadInt64(&br.failed)),
+		Cancelled: cancelled,
+	}
+
+	b.logger.Infof("Broadcast by admin \"%d\" finished: %+v", br.AuthorID, result)
+	onDone(result)
+}
+
+func (b *Broadcaster) send(br *Broadcast, user *model.User) {
+	msg := tgbotapi.NewMessage(user.UserID(), br.Text)
+
+	_, err := b.sender.SendPriority(msg, sender.PriorityLow)
+	switch {
+	case err == nil:
+		atomic.AddInt64(&br.delivered, 1)
+	case sender.IsBlocked(err):
+		atomic.AddInt64(&br.blocked, 1)
+	default:
+		atomic.AddInt64(&br.failed, 1)
+	}
+}
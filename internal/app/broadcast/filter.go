@@ -0,0 +1,82 @@
+package broadcast
+
+import (
+	"qask_telegram/internal/app/locale"
+	"qask_telegram/internal/app/model"
+	"strings"
+)
+
+//Filter selects recipients of a broadcast
+type Filter string
+
+const (
+	//FilterAll selects all users
+	FilterAll Filter = "all"
+	//FilterRegistered selects registered users
+	FilterRegistered Filter = "registered"
+	//FilterQuestions selects users subscribed to questions
+	FilterQuestions Filter = "questions"
+	//FilterMathProblems selects users subscribed to math problems
+	FilterMathProblems Filter = "math"
+
+	languagePrefix = "lang:"
+)
+
+//LanguageFilter selects users with the language
+func LanguageFilter(lang string) Filter {
+	return Filter(languagePrefix + lang)
+}
+
+//Filters returns all available filters
+func Filters() []Filter {
+	filters := []Filter{FilterAll, FilterRegistered, FilterQuestions, FilterMathProblems}
+	for _, lang := range locale.Languages() {
+		filters = append(filters, LanguageFilter(lang))
+	}
+
+	return filters
+}
+
+//Language returns the language of a language filter
+func (f Filter) Language() (string, bool) {
+	if !strings.HasPrefix(string(f), languagePrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(string(f), languagePrefix), true
+}
+
+//IsValid reports whether the filter is known
+func (f Filter) IsValid() bool {
+	for _, filter := range Filters() {
+		if f == filter {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Match reports whether the user is a recipient
+func (f Filter) Match(user *model.User) bool {
+	if user.Banned || user.Inactive {
+		return false
+	}
+
+	if lang, ok := f.Language(); ok {
+		return user.Lang() == lang
+	}
+
+	switch f {
+	case FilterAll:
+		return true
+	case FilterRegistered:
+		return user.Registered
+	case FilterQuestions:
+		return user.Registered && user.QuestSubscribtion
+	case FilterMathProblems:
+		return user.Registered && user.MathProblemSubscribtion
+	}
+
+	return false
+}
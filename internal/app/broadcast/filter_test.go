@@ -0,0 +1,73 @@
+package broadcast
+
+import (
+	"qask_telegram/internal/app/model"
+	"testing"
+)
+
+func testUser(registered bool, questions bool, math bool, lang string) *model.User {
+	user := &model.User{}
+	user.Registered = registered
+	user.QuestSubscribtion = questions
+	user.MathProblemSubscribtion = math
+	user.Language = lang
+
+	return user
+}
+
+func TestFilterMatch(t *testing.T) {
+	guest := testUser(false, false, false, "ru")
+	player := testUser(true, true, false, "ru")
+	mathematician := testUser(true, false, true, "en")
+	banned := testUser(true, true, true, "ru")
+	banned.Banned = true
+	inactive := testUser(true, true, true, "en")
+	inactive.Inactive = true
+
+	testCases := []struct {
+		filter Filter
+		want   []*model.User
+	}{
+		{FilterAll, []*model.User{guest, player, mathematician}},
+		{FilterRegistered, []*model.User{player, mathematician}},
+		{FilterQuestions, []*model.User{player}},
+		{FilterMathProblems, []*model.User{mathematician}},
+		{LanguageFilter("ru"), []*model.User{guest, player}},
+		{LanguageFilter("en"), []*model.User{mathematician}},
+		{Filter("unknown"), nil},
+	}
+
+	users := []*model.User{guest, player, mathematician, banned, inactive}
+	for _, tc := range testCases {
+		var got []*model.User
+		for _, user := range users {
+			if tc.filter.Match(user) {
+				got = append(got, user)
+			}
+		}
+
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: matched %d users, want %d", tc.filter, len(got), len(tc.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: user %d does not match", tc.filter, i)
+			}
+		}
+	}
+}
+
+func TestFilterIsValid(t *testing.T) {
+	for _, filter := range Filters() {
+		if !filter.IsValid() {
+			t.Errorf("filter %q is not valid", filter)
+		}
+	}
+
+	for _, filter := range []Filter{"", "unknown", LanguageFilter("xx")} {
+		if filter.IsValid() {
+			t.Errorf("filter %q is valid", filter)
+		}
+	}
+}
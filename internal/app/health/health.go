@@ -0,0 +1,39 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+//Check returns an error if a component is unhealthy
+type Check func() error
+
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+//Handler runs the checks and responds with 200 if all of them passed or 503 otherwise
+func Handler(checks map[string]Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := &response{
+			Status: "ok",
+			Checks: make(map[string]string, len(checks)),
+		}
+		code := http.StatusOK
+
+		for name, check := range checks {
+			if err := check(); err != nil {
+				resp.Checks[name] = err.Error()
+				resp.Status = "fail"
+				code = http.StatusServiceUnavailable
+			} else {
+				resp.Checks[name] = "ok"
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,310 @@
+package locale
+
+var en = Catalog{
+	"help.unregistered": `The following commands are available:
+/help - help
+/start - registration
+`,
+	"help.registered": `The following commands are available:
+/play - play
+/profile - profile settings
+/favorites - favorite questions
+/history - latest questions
+/invite - invite friends
+/submit - suggest a question
+/team - team play
+/newpass - generate a new password
+`,
+
+	"error.unknown_command":     "Error! Unknown command",
+	"error.unavailable_command": "Unavailable command",
+	"error.unknown_user":        "Send /start to begin",
+	"error.internal":            "An internal error occurred:\n\"%s\"\nPlease try again later.",
+	"error.service_unavailable": "The service is temporarily unavailable. Please try again later.",
+	"error.no_questions":        "There are no questions matching your settings. Try other categories or difficulty.",
+
+	"start.already_registered": "You are already registered!",
+
+	"welcome.text": `Welcome, %s!
+You need to register to play. Try it right now by pressing the "Register" button.`,
+	"welcome.register":   "Register",
+	"welcome.registered": "Registration completed successfully",
+
+	"profile.title":            "Profile settings",
+	"profile.first_name":       "Name [%s]",
+	"profile.user_name":        "Username [%s]",
+	"profile.language":         "Language [%s]",
+	"profile.enter_first_name": "Okay, enter a new name",
+	"profile.enter_user_name":  "Okay, enter a new username",
+	"profile.choose_language":  "Choose a language",
+	"profile.language_changed": "Language changed",
+
+	"language.auto": "Automatic",
+	"language.ru":   "Русский",
+	"language.en":   "English",
+
+	"play.title":           "Choose an action:",
+	"play.random_question": "Random question",
+	"play.math_problem":    "Math problem",
+	"play.settings":        "Game settings",
+
+	"settings.title":         "Game settings",
+	"settings.subscriptions": "Subscriptions",
+
+	"subscriptions.title":         "Subscription settings",
+	"subscriptions.questions":     "Receive questions",
+	"subscriptions.math_problems": "Receive math problems",
+
+	"button.back": "<< back",
+
+	"question.show_answer":   "Show answer",
+	"question.show_question": "Show question",
+	"question.show_comment":  "Show comment",
+	"question.report":        "Report a problem",
+	"question.next":          "Next question",
+
+	"questions.one":   "%d question",
+	"questions.other": "%d questions",
+
+	"antiflood.muted": "Too many requests. The bot will not respond to you for %s.",
+
+	"minutes.one":   "%d minute",
+	"minutes.other": "%d minutes",
+
+	"answer.correct":   "Correct!",
+	"answer.incorrect": "Wrong. Try again or press \"Show answer\".",
+
+	"help.admin": `Admin commands:
+/admin users - user statistics
+/admin user <id> - user details
+/admin ban <id> - ban a user
+/admin unban <id> - unban a user
+/admin reports - recent problem reports
+/admin submissions - questions awaiting moderation
+/admin reload - reload the config
+/broadcast - send a message to users
+`,
+
+	"admin.users":                "Users: %d\nRegistered: %d\nActive: %d\nBanned: %d\nBlocked the bot: %d",
+	"admin.recent_registrations": "Recent registrations:",
+	"admin.user_id_required":     "Specify a user ID",
+	"admin.user_not_found":       "User %d not found",
+	"admin.user_info": `ID: %d
+Name: %s
+Username: %s
+Language: %s
+Registered: %t
+Banned: %t
+Blocked the bot: %t
+Question subscription: %t
+Math problem subscription: %t
+Created: %s
+Last seen: %s
+Current question: %s`,
+	"admin.cannot_ban_admin": "An admin can not be banned",
+	"admin.banned":           "User %d is banned",
+	"admin.unbanned":         "User %d is unbanned",
+	"admin.no_reports":       "There are no problem reports",
+	"admin.report_question":  "Question: %s",
+	"admin.reloaded":         "Config reloaded",
+
+	"report.describe": "Describe the problem in one message",
+	"report.sent":     "Thank you! The problem report has been sent",
+
+	"broadcast.enter_text":      "Send the text of the broadcast",
+	"broadcast.already_running": "Your broadcast is not finished yet",
+	"broadcast.draft":           "The broadcast preview is above.\nRecipients: %s (%d)",
+	"broadcast.send":            "Send",
+	"broadcast.cancel":          "Cancel",
+	"broadcast.stop":            "Stop",
+	"broadcast.running":         "The broadcast is being sent...",
+	"broadcast.discarded":       "The broadcast is cancelled",
+	"broadcast.done":            "The broadcast is finished.\nRecipients: %d\nDelivered: %d\nBlocked the bot: %d\nFailed: %d",
+	"broadcast.cancelled":       "The broadcast is stopped.\nRecipients: %d\nDelivered: %d\nBlocked the bot: %d\nFailed: %d",
+
+	"broadcast.filter.all":        "All",
+	"broadcast.filter.registered": "Registered",
+	"broadcast.filter.questions":  "Question subscribers",
+	"broadcast.filter.math":       "Math problem subscribers",
+	"broadcast.filter.language":   "Language: %s",
+
+	"group.help": `Let's play together! The bot asks a question, answer with a message in the chat. The first correct answer wins a point.
+/question - new question
+/scores - scoreboard
+/help - help
+`,
+	"group.question":     "❓ Question:\n%s\n\nAnswer with a message in the chat",
+	"group.correct":      "🎉 %s is right! The answer is: %s",
+	"group.answer":       "%s\n\nAnswer: %s",
+	"group.no_question":  "There is no active question. New question: /question",
+	"group.scores":       "Scoreboard:",
+	"group.scores_empty": "Nobody has answered correctly yet",
+
+	"points.one":   "%d point",
+	"points.other": "%d points",
+
+	"settings.timer": "Timer: %s",
+
+	"timer.title":    "Time to answer",
+	"timer.off":      "off",
+	"timer.seconds":  "%d s",
+	"timer.left":     "⏱ Time left: %d s",
+	"timer.expired":  "⌛ Time is up!",
+	"timer.too_late": "The time to answer has run out",
+
+	"play.session":        "Game of %d questions",
+	"play.resume_session": "Resume the game (%d/%d)",
+
+	"session.progress": "Question %d of %d",
+	"session.skip":     "Skip",
+	"session.pause":    "Pause",
+	"session.resume":   "Resume",
+	"session.paused":   "⏸ The game is paused. Questions played: %d of %d.\nResume it here or in the /play menu",
+	"session.summary": `🏁 Game over!
+Correct answers: %d of %d
+Skipped: %d
+Average time per question: %d s
+Game duration: %d min`,
+	"session.share":        "My Qask result: %d of %d correct answers!",
+	"session.share_button": "Share the result",
+	"session.again":        "Play again",
+
+	"settings.categories": "Categories: %s",
+	"settings.difficulty": "Difficulty: %s",
+
+	"categories.title": "Choose question categories",
+	"categories.all":   "all",
+	"categories.one":   "%d category",
+	"categories.other": "%d categories",
+
+	"difficulty.title": "Choose question difficulty",
+	"difficulty.0":     "any",
+	"difficulty.1":     "easy",
+	"difficulty.2":     "medium",
+	"difficulty.3":     "hard",
+
+	"settings.reset_history": "Reset question history",
+	"history.reset":          "Question history cleared (%s). Questions may repeat.",
+
+	"favorites.add":           "⭐ Add to favorites",
+	"favorites.added":         "The question is added to favorites. Favorites: /favorites",
+	"favorites.already_added": "The question is already in favorites",
+	"favorites.empty":         "There is nothing in favorites yet. Add questions with the \"⭐ Add to favorites\" button.",
+	"favorites.title":         "⭐ Favorite questions (page %d of %d):",
+	"favorites.answer":        "Answer: %s",
+
+	"history.empty": "You have not received any questions yet",
+	"history.title": "Latest questions (%d):",
+
+	"play.review": "🔁 Review (%d)",
+
+	"review.forgot": "Forgot",
+	"review.hard":   "Hard",
+	"review.easy":   "Easy",
+	"review.none":   "There are no questions to review now. Missed questions and favorites get here.",
+
+	"question.hint": "💡 Hint",
+
+	"hint.length":  "The answer has %s:",
+	"hint.letters": "Revealed letters of the answer:",
+	"hint.points":  "A correct answer now gives %s",
+	"hint.no_more": "There are no more hints",
+
+	"letters.one":   "%d letter",
+	"letters.other": "%d letters",
+
+	"answer.points": "+%s (total: %d)",
+
+	"quiz.choose": "Choose an option:",
+
+	"inline.question":  "❓ Question:\n%s",
+	"inline.expired":   "This question is outdated, the answer is not available",
+	"inline.opened_by": "The answer was opened by %s",
+
+	"profile.referrals": "Friends invited: %d. Your link: /invite",
+
+	"invite.text": `Invite friends to play together! Your link:
+%s
+
+Friends invited: %d`,
+	"invite.share":                   "Share the link",
+	"invite.share_text":              "Let's play Qask together!",
+	"invite.joined":                  "🎉 %s has joined with your link",
+	"invite.question_not_found":      "The question of the link is not found, it may be outdated",
+	"invite.question_after_register": "The question of the link opens right after the registration",
+	"invite.question_in_session":     "Finish or pause the current game first, then open the link again",
+
+	"question.share":      "Share",
+	"question.share_text": "Try to answer this question!",
+
+	"submit.start":          "Suggest your question! It gets into the game after a moderator checks it.",
+	"submit.enter.question": "Send the text of the question",
+	"submit.enter.answer":   "Send the answer",
+	"submit.enter.comment":  "Send a comment on the answer",
+	"submit.enter.source":   "Send the source",
+	"submit.skip":           "Send \"%s\" to skip",
+	"submit.invalid":        "The length must be from %d to %d characters, please try again",
+	"submit.preview":        "Check the question before sending:",
+	"submit.fields": `Question: %s
+Answer: %s
+Comment: %s
+Source: %s`,
+	"submit.send":              "Send for moderation",
+	"submit.cancel":            "Cancel",
+	"submit.sent":              "Thank you! The question is sent for moderation, we will let you know the decision.",
+	"submit.cancelled":         "The question is not sent",
+	"submit.card":              "📝 Question #%d by %s",
+	"submit.approve":           "Approve",
+	"submit.edit":              "Edit",
+	"submit.reject":            "Reject",
+	"submit.none":              "There are no questions awaiting moderation",
+	"submit.already_moderated": "Question #%d is moderated already",
+	"submit.approve_failed":    "Can not add the question to qask:\n\"%s\"",
+	"submit.approved_by":       "✅ Approved by %s",
+	"submit.rejected_by":       "❌ Rejected by %s\nReason: %s",
+	"submit.approved":          "🎉 Your question \"%s\" is approved and added to the game. Thank you!",
+	"submit.rejected":          "Your question \"%s\" is rejected by a moderator.\nReason: %s",
+	"submit.enter_reason":      "Send the reason to reject question #%d, the author will see it",
+	"submit.choose_field":      "What to change in question #%d?",
+	"submit.current":           "Now: %s",
+
+	"submit.field.question": "Question",
+	"submit.field.answer":   "Answer",
+	"submit.field.comment":  "Comment",
+	"submit.field.source":   "Source",
+
+	"profile.team":    "Team [%s]",
+	"profile.no_team": "none",
+	"play.team":       "👥 Team",
+
+	"team.none":              "You are not in a team. Create your own or join a team with an invite code.",
+	"team.create":            "Create a team",
+	"team.join":              "Join by code",
+	"team.leaderboard":       "🏆 Team leaderboard",
+	"team.info":              "👥 Team \"%s\"\nCaptain: %s\nPlayers: %d — %s\nInvite code: %s",
+	"team.stats":             "Questions played: %d, correct answers: %d, %s in total",
+	"team.question_button":   "Team question",
+	"team.leave":             "Leave the team",
+	"team.already_member":    "You are already in a team, leave it first",
+	"team.invalid_name":      "A team name must be %d to %d characters long, try again",
+	"team.enter_name":        "Okay, enter the name of the team",
+	"team.created":           "Team \"%s\" is created and you are its captain. To invite players send them the code: %s",
+	"team.enter_code":        "Okay, enter the invite code",
+	"team.not_found":         "There is no team with this code",
+	"team.full":              "The team already has %d players, there are no free places",
+	"team.member_joined":     "👋 %s joins the team",
+	"team.member_left":       "%s leaves the team",
+	"team.new_captain":       "You are the captain of the team now",
+	"team.left":              "You have left team \"%s\"",
+	"team.captain_only":      "Only the captain can ask questions and answer for the team",
+	"team.question":          "❓ Question for team \"%s\"",
+	"team.answer_button":     "Answer for the team",
+	"team.discuss":           "Discuss the question with your team, the captain will send the answer",
+	"team.answer":            "Captain %s did not answer.\nThe correct answer: %s",
+	"team.correct":           "✅ Captain %s answered correctly!\nThe correct answer: %s",
+	"team.no_question":       "The team has no current question",
+	"team.incorrect":         "❌ The captain's answer is wrong",
+	"team.enter_answer":      "Enter the team's answer",
+	"team.leaderboard_empty": "Teams have not played any questions yet",
+	"team.leaderboard_title": "🏆 Top teams:",
+}
@@ -0,0 +1,75 @@
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//DefaultLanguage is used when a user's language is unknown or unsupported
+const DefaultLanguage = "ru"
+
+//Catalog is a set of translated strings of one language
+type Catalog map[string]string
+
+var catalogs = map[string]Catalog{
+	"ru": ru,
+	"en": en,
+}
+
+//Languages returns codes of all supported languages
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+//IsSupported reports whether there is a catalog for the language
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+//Normalize converts a telegram language code ("en-US", "ru") to a supported language
+func Normalize(code string) string {
+	code = strings.ToLower(code)
+	if i := strings.IndexAny(code, "-_"); i != -1 {
+		code = code[:i]
+	}
+
+	if !IsSupported(code) {
+		return DefaultLanguage
+	}
+
+	return code
+}
+
+//Get returns the translation of the key formatted with args.
+//Missing translations fall back to the default language and then to the key itself
+func Get(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+//Plural returns the plural form of the key matching n.
+//Plural forms are stored as "<key>.<form>", e.g. "points.one", "points.many"
+func Plural(lang, key string, n int, args ...interface{}) string {
+	lang = Normalize(lang)
+	return Get(lang, key+"."+pluralForm(lang, n), args...)
+}
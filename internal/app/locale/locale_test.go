@@ -0,0 +1,97 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+)
+
+func splitPluralKey(key string) (string, bool) {
+	i := strings.LastIndex(key, ".")
+	if i == -1 {
+		return key, false
+	}
+
+	switch key[i+1:] {
+	case formOne, formFew, formMany, formOther:
+		return key[:i], true
+	}
+
+	return key, false
+}
+
+func TestCatalogsHaveAllKeys(t *testing.T) {
+	keys := make(map[string]bool)
+	plurals := make(map[string]bool)
+
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			if base, ok := splitPluralKey(key); ok {
+				plurals[base] = true
+			} else {
+				keys[key] = true
+			}
+		}
+	}
+
+	for lang, catalog := range catalogs {
+		for key := range keys {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("locale %q: missing key %q", lang, key)
+			}
+		}
+
+		rule, ok := pluralRules[lang]
+		if !ok {
+			t.Errorf("locale %q: no plural rule", lang)
+			continue
+		}
+
+		for base := range plurals {
+			for _, form := range rule.forms {
+				if _, ok := catalog[base+"."+form]; !ok {
+					t.Errorf("locale %q: missing plural form %q", lang, base+"."+form)
+				}
+			}
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	testCases := []struct {
+		lang string
+		n    int
+		want string
+	}{
+		{"ru", 1, "1 вопрос"},
+		{"ru", 3, "3 вопроса"},
+		{"ru", 5, "5 вопросов"},
+		{"ru", 11, "11 вопросов"},
+		{"ru", 21, "21 вопрос"},
+		{"ru", 112, "112 вопросов"},
+		{"en", 1, "1 question"},
+		{"en", 2, "2 questions"},
+		{"en-US", 0, "0 questions"},
+	}
+
+	for _, tc := range testCases {
+		if got := Plural(tc.lang, "questions", tc.n, tc.n); got != tc.want {
+			t.Errorf("Plural(%q, %d) = %q, want %q", tc.lang, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := map[string]string{
+		"en":    "en",
+		"en-US": "en",
+		"RU":    "ru",
+		"de":    DefaultLanguage,
+		"":      DefaultLanguage,
+	}
+
+	for code, want := range testCases {
+		if got := Normalize(code); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
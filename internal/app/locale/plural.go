@@ -0,0 +1,52 @@
+package locale
+
+const (
+	formOne   = "one"
+	formFew   = "few"
+	formMany  = "many"
+	formOther = "other"
+)
+
+type pluralRule struct {
+	forms  []string
+	choose func(n int) string
+}
+
+var pluralRules = map[string]pluralRule{
+	"ru": {
+		forms: []string{formOne, formFew, formMany},
+		choose: func(n int) string {
+			if n < 0 {
+				n = -n
+			}
+
+			switch {
+			case n%10 == 1 && n%100 != 11:
+				return formOne
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return formFew
+			default:
+				return formMany
+			}
+		},
+	},
+	"en": {
+		forms: []string{formOne, formOther},
+		choose: func(n int) string {
+			if n == 1 || n == -1 {
+				return formOne
+			}
+
+			return formOther
+		},
+	},
+}
+
+func pluralForm(lang string, n int) string {
+	rule, ok := pluralRules[lang]
+	if !ok {
+		rule = pluralRules[DefaultLanguage]
+	}
+
+	return rule.choose(n)
+}
@@ -0,0 +1,315 @@
+package locale
+
+var ru = Catalog{
+	"help.unregistered": `Вам доступны следующие команды:
+/help - справка
+/start - регистрация
+`,
+	"help.registered": `Вам доступны следующие команды:
+/play - играть
+/profile - настройки профиля
+/favorites - избранные вопросы
+/history - последние вопросы
+/invite - пригласить друзей
+/submit - предложить свой вопрос
+/team - командная игра
+/newpass - сгенерировать новый пароль
+`,
+
+	"error.unknown_command":     "Ошибка! Неизвестная команда",
+	"error.unavailable_command": "Недоступная команда",
+	"error.unknown_user":        "Отправьте /start, чтобы начать",
+	"error.internal":            "Произошла внутренняя ошибка:\n\"%s\"\nПожалуйста, повторите попытку позже.",
+	"error.service_unavailable": "Сервис временно недоступен. Пожалуйста, повторите попытку позже.",
+	"error.no_questions":        "Нет вопросов, подходящих под ваши настройки. Попробуйте выбрать другие категории или сложность.",
+
+	"start.already_registered": "Вы уже зарегистрированы!",
+
+	"welcome.text": `Добро пожаловать, %s!
+Для игры необходимо зарегистрироваться. Попробуй сделать это прямо сейчас, нажав кнопку "Зарегистрироваться".`,
+	"welcome.register":   "Зарегистрироваться",
+	"welcome.registered": "Регистрация прошла успешно",
+
+	"profile.title":            "Настройки профиля",
+	"profile.first_name":       "Имя [%s]",
+	"profile.user_name":        "Имя пользователя [%s]",
+	"profile.language":         "Язык [%s]",
+	"profile.enter_first_name": "Окей, введите новое имя",
+	"profile.enter_user_name":  "Окей, введите новое имя пользователя",
+	"profile.choose_language":  "Выберите язык",
+	"profile.language_changed": "Язык изменён",
+
+	"language.auto": "Автоматически",
+	"language.ru":   "Русский",
+	"language.en":   "English",
+
+	"play.title":           "Выберите действие:",
+	"play.random_question": "Случайный вопрос",
+	"play.math_problem":    "Математическая задача",
+	"play.settings":        "Настройки игры",
+
+	"settings.title":         "Настройки игры",
+	"settings.subscriptions": "Подписки",
+
+	"subscriptions.title":         "Настройки подписок",
+	"subscriptions.questions":     "Получать вопросы",
+	"subscriptions.math_problems": "Получать математические задачи",
+
+	"button.back": "<< назад",
+
+	"question.show_answer":   "Показать ответ",
+	"question.show_question": "Показать вопрос",
+	"question.show_comment":  "Показать комментарий",
+	"question.report":        "Сообщить о проблеме",
+	"question.next":          "Следующий вопрос",
+
+	"questions.one":  "%d вопрос",
+	"questions.few":  "%d вопроса",
+	"questions.many": "%d вопросов",
+
+	"antiflood.muted": "Слишком много запросов. Бот не будет отвечать вам %s.",
+
+	"minutes.one":  "%d минуту",
+	"minutes.few":  "%d минуты",
+	"minutes.many": "%d минут",
+
+	"answer.correct":   "Правильно!",
+	"answer.incorrect": "Неправильно. Попробуйте ещё раз или нажмите «Показать ответ».",
+
+	"help.admin": `Команды администратора:
+/admin users - статистика пользователей
+/admin user <id> - информация о пользователе
+/admin ban <id> - заблокировать пользователя
+/admin unban <id> - разблокировать пользователя
+/admin reports - последние сообщения о проблемах
+/admin submissions - вопросы на модерации
+/admin reload - перечитать конфигурацию
+/broadcast - рассылка сообщения пользователям
+`,
+
+	"admin.users":                "Пользователей: %d\nЗарегистрировано: %d\nАктивных: %d\nЗаблокировано: %d\nЗаблокировали бота: %d",
+	"admin.recent_registrations": "Последние регистрации:",
+	"admin.user_id_required":     "Укажите ID пользователя",
+	"admin.user_not_found":       "Пользователь %d не найден",
+	"admin.user_info": `ID: %d
+Имя: %s
+Имя пользователя: %s
+Язык: %s
+Зарегистрирован: %t
+Заблокирован: %t
+Заблокировал бота: %t
+Подписка на вопросы: %t
+Подписка на задачи: %t
+Создан: %s
+Последняя активность: %s
+Текущий вопрос: %s`,
+	"admin.cannot_ban_admin": "Нельзя заблокировать администратора",
+	"admin.banned":           "Пользователь %d заблокирован",
+	"admin.unbanned":         "Пользователь %d разблокирован",
+	"admin.no_reports":       "Сообщений о проблемах нет",
+	"admin.report_question":  "Вопрос: %s",
+	"admin.reloaded":         "Конфигурация перечитана",
+
+	"report.describe": "Опишите проблему одним сообщением",
+	"report.sent":     "Спасибо! Сообщение о проблеме отправлено",
+
+	"broadcast.enter_text":      "Отправьте текст рассылки",
+	"broadcast.already_running": "Ваша рассылка ещё не завершена",
+	"broadcast.draft":           "Предпросмотр рассылки выше.\nПолучатели: %s (%d)",
+	"broadcast.send":            "Отправить",
+	"broadcast.cancel":          "Отмена",
+	"broadcast.stop":            "Остановить",
+	"broadcast.running":         "Рассылка отправляется...",
+	"broadcast.discarded":       "Рассылка отменена",
+	"broadcast.done":            "Рассылка завершена.\nПолучателей: %d\nДоставлено: %d\nЗаблокировали бота: %d\nОшибок: %d",
+	"broadcast.cancelled":       "Рассылка остановлена.\nПолучателей: %d\nДоставлено: %d\nЗаблокировали бота: %d\nОшибок: %d",
+
+	"broadcast.filter.all":        "Все",
+	"broadcast.filter.registered": "Зарегистрированные",
+	"broadcast.filter.questions":  "Подписчики вопросов",
+	"broadcast.filter.math":       "Подписчики задач",
+	"broadcast.filter.language":   "Язык: %s",
+
+	"group.help": `Играем всем чатом! Бот задаёт вопрос, отвечайте сообщением в чат. Первый правильный ответ приносит очко.
+/question - новый вопрос
+/scores - таблица результатов
+/help - справка
+`,
+	"group.question":     "❓ Вопрос:\n%s\n\nОтвечайте сообщением в чат",
+	"group.correct":      "🎉 %s отвечает правильно! Ответ: %s",
+	"group.answer":       "%s\n\nОтвет: %s",
+	"group.no_question":  "Сейчас нет активного вопроса. Новый вопрос: /question",
+	"group.scores":       "Таблица результатов:",
+	"group.scores_empty": "Пока никто не ответил правильно",
+
+	"points.one":  "%d очко",
+	"points.few":  "%d очка",
+	"points.many": "%d очков",
+
+	"settings.timer": "Таймер: %s",
+
+	"timer.title":    "Время на ответ",
+	"timer.off":      "выключен",
+	"timer.seconds":  "%d с",
+	"timer.left":     "⏱ Осталось: %d с",
+	"timer.expired":  "⌛ Время вышло!",
+	"timer.too_late": "Время на ответ истекло",
+
+	"play.session":        "Игра на %d вопросов",
+	"play.resume_session": "Продолжить игру (%d/%d)",
+
+	"session.progress": "Вопрос %d из %d",
+	"session.skip":     "Пропустить",
+	"session.pause":    "Пауза",
+	"session.resume":   "Продолжить",
+	"session.paused":   "⏸ Игра на паузе. Сыграно вопросов: %d из %d.\nПродолжить можно здесь или в меню /play",
+	"session.summary": `🏁 Игра окончена!
+Правильных ответов: %d из %d
+Пропущено: %d
+Среднее время на вопрос: %d с
+Длительность игры: %d мин`,
+	"session.share":        "Мой результат в Qask: %d из %d правильных ответов!",
+	"session.share_button": "Поделиться результатом",
+	"session.again":        "Сыграть ещё",
+
+	"settings.categories": "Категории: %s",
+	"settings.difficulty": "Сложность: %s",
+
+	"categories.title": "Выберите категории вопросов",
+	"categories.all":   "все",
+	"categories.one":   "%d категория",
+	"categories.few":   "%d категории",
+	"categories.many":  "%d категорий",
+
+	"difficulty.title": "Выберите сложность вопросов",
+	"difficulty.0":     "любая",
+	"difficulty.1":     "лёгкая",
+	"difficulty.2":     "средняя",
+	"difficulty.3":     "сложная",
+
+	"settings.reset_history": "Сбросить историю вопросов",
+	"history.reset":          "История вопросов очищена (%s). Вопросы могут повторяться.",
+
+	"favorites.add":           "⭐ В избранное",
+	"favorites.added":         "Вопрос добавлен в избранное. Список избранного: /favorites",
+	"favorites.already_added": "Вопрос уже в избранном",
+	"favorites.empty":         "В избранном пока ничего нет. Добавляйте вопросы кнопкой «⭐ В избранное».",
+	"favorites.title":         "⭐ Избранные вопросы (страница %d из %d):",
+	"favorites.answer":        "Ответ: %s",
+
+	"history.empty": "Вы ещё не получили ни одного вопроса",
+	"history.title": "Последние вопросы (%d):",
+
+	"play.review": "🔁 Повторение (%d)",
+
+	"review.forgot": "Не помню",
+	"review.hard":   "Трудно",
+	"review.easy":   "Легко",
+	"review.none":   "Сейчас нет вопросов для повторения. Сюда попадают пропущенные вопросы и вопросы из избранного.",
+
+	"question.hint": "💡 Подсказка",
+
+	"hint.length":  "В ответе %s:",
+	"hint.letters": "Открытые буквы ответа:",
+	"hint.points":  "За правильный ответ теперь можно получить %s",
+	"hint.no_more": "Подсказок больше нет",
+
+	"letters.one":  "%d буква",
+	"letters.few":  "%d буквы",
+	"letters.many": "%d букв",
+
+	"answer.points": "+%s (всего: %d)",
+
+	"quiz.choose": "Выберите вариант ответа:",
+
+	"inline.question":  "❓ Вопрос:\n%s",
+	"inline.expired":   "Этот вопрос устарел, ответ недоступен",
+	"inline.opened_by": "Ответ открыл(а) %s",
+
+	"profile.referrals": "Приглашено друзей: %d. Ваша ссылка: /invite",
+
+	"invite.text": `Приглашайте друзей играть вместе! Ваша ссылка:
+%s
+
+Приглашено друзей: %d`,
+	"invite.share":                   "Поделиться ссылкой",
+	"invite.share_text":              "Давай играть в Qask вместе!",
+	"invite.joined":                  "🎉 По вашей ссылке присоединился %s",
+	"invite.question_not_found":      "Вопрос по ссылке не найден, возможно, он устарел",
+	"invite.question_after_register": "Вопрос по ссылке откроется сразу после регистрации",
+	"invite.question_in_session":     "Сначала закончите текущую игру или поставьте её на паузу, затем откройте ссылку ещё раз",
+
+	"question.share":      "Поделиться",
+	"question.share_text": "Попробуй ответить на этот вопрос!",
+
+	"submit.start":          "Предложите свой вопрос! После проверки модератором он попадёт в игру.",
+	"submit.enter.question": "Отправьте текст вопроса",
+	"submit.enter.answer":   "Отправьте ответ",
+	"submit.enter.comment":  "Отправьте комментарий к ответу",
+	"submit.enter.source":   "Отправьте источник",
+	"submit.skip":           "Чтобы пропустить, отправьте \"%s\"",
+	"submit.invalid":        "Длина должна быть от %d до %d символов, попробуйте ещё раз",
+	"submit.preview":        "Проверьте вопрос перед отправкой:",
+	"submit.fields": `Вопрос: %s
+Ответ: %s
+Комментарий: %s
+Источник: %s`,
+	"submit.send":              "Отправить на модерацию",
+	"submit.cancel":            "Отменить",
+	"submit.sent":              "Спасибо! Вопрос отправлен на модерацию, мы сообщим о решении.",
+	"submit.cancelled":         "Вопрос не отправлен",
+	"submit.card":              "📝 Вопрос #%d от %s",
+	"submit.approve":           "Одобрить",
+	"submit.edit":              "Изменить",
+	"submit.reject":            "Отклонить",
+	"submit.none":              "Нет вопросов на модерации",
+	"submit.already_moderated": "Вопрос #%d уже проверен",
+	"submit.approve_failed":    "Не удалось добавить вопрос в qask:\n\"%s\"",
+	"submit.approved_by":       "✅ Одобрен: %s",
+	"submit.rejected_by":       "❌ Отклонён: %s\nПричина: %s",
+	"submit.approved":          "🎉 Ваш вопрос \"%s\" одобрен и добавлен в игру. Спасибо!",
+	"submit.rejected":          "Ваш вопрос \"%s\" отклонён модератором.\nПричина: %s",
+	"submit.enter_reason":      "Отправьте причину отклонения вопроса #%d, автор её увидит",
+	"submit.choose_field":      "Что изменить в вопросе #%d?",
+	"submit.current":           "Сейчас: %s",
+
+	"submit.field.question": "Вопрос",
+	"submit.field.answer":   "Ответ",
+	"submit.field.comment":  "Комментарий",
+	"submit.field.source":   "Источник",
+
+	"profile.team":    "Команда [%s]",
+	"profile.no_team": "нет",
+	"play.team":       "👥 Команда",
+
+	"team.none":              "Вы не состоите в команде. Создайте свою или вступите в команду по коду приглашения.",
+	"team.create":            "Создать команду",
+	"team.join":              "Вступить по коду",
+	"team.leaderboard":       "🏆 Рейтинг команд",
+	"team.info":              "👥 Команда «%s»\nКапитан: %s\nИгроков: %d — %s\nКод приглашения: %s",
+	"team.stats":             "Сыграно вопросов: %d, верных ответов: %d, всего %s",
+	"team.question_button":   "Вопрос команде",
+	"team.leave":             "Покинуть команду",
+	"team.already_member":    "Вы уже состоите в команде, сначала покиньте её",
+	"team.invalid_name":      "Название команды должно быть от %d до %d символов, попробуйте ещё раз",
+	"team.enter_name":        "Окей, введите название команды",
+	"team.created":           "Команда «%s» создана, вы её капитан. Чтобы пригласить игроков, отправьте им код: %s",
+	"team.enter_code":        "Окей, введите код приглашения",
+	"team.not_found":         "Команда с таким кодом не найдена",
+	"team.full":              "В команде уже %d игроков, свободных мест нет",
+	"team.member_joined":     "👋 %s присоединяется к команде",
+	"team.member_left":       "%s покидает команду",
+	"team.new_captain":       "Теперь вы капитан команды",
+	"team.left":              "Вы покинули команду «%s»",
+	"team.captain_only":      "Только капитан может задавать вопросы и отвечать за команду",
+	"team.question":          "❓ Вопрос команде «%s»",
+	"team.answer_button":     "Ответить за команду",
+	"team.discuss":           "Обсудите вопрос с командой, ответ отправит капитан",
+	"team.answer":            "Капитан %s не стал отвечать.\nПравильный ответ: %s",
+	"team.correct":           "✅ Капитан %s ответил верно!\nПравильный ответ: %s",
+	"team.no_question":       "У команды нет текущего вопроса",
+	"team.incorrect":         "❌ Ответ капитана неверный",
+	"team.enter_answer":      "Введите ответ команды",
+	"team.leaderboard_empty": "Команды ещё не сыграли ни одного вопроса",
+	"team.leaderboard_title": "🏆 Лучшие команды:",
+}
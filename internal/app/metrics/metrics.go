@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "qask_telegram"
+
+var (
+	//UpdatesReceived counts telegram updates by type
+	UpdatesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_received_total",
+		Help:      "Number of telegram updates received by type.",
+	}, []string{"type"})
+
+	//HandlerDuration observes handler latency per router and route
+	HandlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Latency of update handlers by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"router", "route"})
+
+	//QaskRequests counts requests to qask by endpoint and status
+	QaskRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "qask_requests_total",
+		Help:      "Number of requests to qask by endpoint and response status.",
+	}, []string{"endpoint", "status"})
+
+	//QaskRequestDuration observes qask latency per endpoint
+	QaskRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "qask_request_duration_seconds",
+		Help:      "Latency of requests to qask by endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	//QaskErrors counts failed requests to qask by endpoint
+	QaskErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "qask_errors_total",
+		Help:      "Number of failed requests to qask by endpoint.",
+	}, []string{"endpoint"})
+
+	//SendErrors counts telegram send errors by code
+	SendErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "send_errors_total",
+		Help:      "Number of telegram send errors by error code.",
+	}, []string{"code"})
+
+	//QuestionsServed counts questions sent to users
+	QuestionsServed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "questions_served_total",
+		Help:      "Number of questions sent to users.",
+	})
+
+	//Answers counts users' answers by result
+	Answers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "answers_total",
+		Help:      "Number of answers by result (correct, incorrect, revealed, expired).",
+	}, []string{"result"})
+
+	//QuestionsShared counts questions posted to chats through inline mode
+	QuestionsShared = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "questions_shared_total",
+		Help:      "Number of questions posted through inline mode.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		UpdatesReceived,
+		HandlerDuration,
+		QaskRequests,
+		QaskRequestDuration,
+		QaskErrors,
+		SendErrors,
+		QuestionsServed,
+		Answers,
+		QuestionsShared,
+	)
+}
+
+//RegisterGauge registers a gauge calculated on every scrape
+func RegisterGauge(name, help string, function func() float64) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, function))
+}
+
+//RegisterCounter registers a counter read on every scrape
+func RegisterCounter(name, help string, function func() float64) {
+	prometheus.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, function))
+}
+
+//Handler returns an http handler exposing the metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"qask_telegram/internal/app/locale"
+	"sort"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//GroupScore is a score of a group member
+type GroupScore struct {
+	UserID int
+	Name   string
+	Points int
+}
+
+//Group is a group chat playing the quiz, its scoreboard is separate from members' private stats
+type Group struct {
+	mu              sync.Mutex
+	ChatID          int64
+	Title           string
+	LanguageCode    string
+	Question        *Question
+	QuestionMessage tgbotapi.Message
+	Answered        bool
+	Scores          map[int]*GroupScore
+}
+
+//SetQuestion starts a new round with the question
+func (g *Group) SetQuestion(q *Question) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.Question = q
+	g.Answered = false
+}
+
+//TryAnswer checks an answer of a group member, only the first correct answer to a question wins a point.
+//It returns the question if the answer has won
+func (g *Group) TryAnswer(from *tgbotapi.User, answer string) *Question {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Question == nil || g.Answered || !g.Question.CheckAnswer(answer) {
+		return nil
+	}
+
+	g.Answered = true
+
+	score, ok := g.Scores[from.ID]
+	if !ok {
+		score = &GroupScore{
+			UserID: from.ID,
+		}
+		g.Scores[from.ID] = score
+	}
+	score.Name = from.FirstName
+	score.Points++
+
+	return g.Question
+}
+
+//Open reports whether the group's question is waiting for an answer
+func (g *Group) Open() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.Question != nil && !g.Answered
+}
+
+//CloseQuestion stops accepting answers and returns the question if it was open
+func (g *Group) CloseQuestion() *Question {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.Question == nil || g.Answered {
+		return nil
+	}
+
+	g.Answered = true
+	return g.Question
+}
+
+//Scoreboard returns scores sorted by points
+func (g *Group) Scoreboard() []GroupScore {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	scores := make([]GroupScore, 0, len(g.Scores))
+	for _, score := range g.Scores {
+		scores = append(scores, *score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Points > scores[j].Points
+	})
+
+	return scores
+}
+
+//Lang returns the language of the group
+func (g *Group) Lang() string {
+	return locale.Normalize(g.LanguageCode)
+}
+
+//Tr returns the message translated to the group's language
+func (g *Group) Tr(key string, args ...interface{}) string {
+	return locale.Get(g.Lang(), key, args...)
+}
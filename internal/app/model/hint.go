@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+//MaxPoints is the number of points for a question answered without hints, each hint costs a point
+const MaxPoints = 5
+
+//QuestionPoints returns the points for a correct answer given after the number of hints, at least one point
+func QuestionPoints(hints int) int {
+	points := MaxPoints - hints
+	if points < 1 {
+		points = 1
+	}
+
+	return points
+}
+
+func isAnswerLetter(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+//AnswerLetters returns the number of letters and digits in the answer
+func (q *Question) AnswerLetters() int {
+	letters := 0
+	for _, r := range q.Answer {
+		if isAnswerLetter(r) {
+			letters++
+		}
+	}
+
+	return letters
+}
+
+//MaxHints returns the number of hints for the question: the length of the answer and then its letters one by one,
+//the last letter is never revealed
+func (q *Question) MaxHints() int {
+	return q.AnswerLetters()
+}
+
+//MaskAnswer returns the answer with all letters but the first revealed ones replaced by underscores,
+//punctuation is kept and letters are separated by spaces to be countable
+func (q *Question) MaskAnswer(revealed int) string {
+	var b strings.Builder
+	for _, r := range q.Answer {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteString("  ")
+			continue
+		case !isAnswerLetter(r):
+			b.WriteRune(r)
+		case revealed > 0:
+			b.WriteRune(r)
+			revealed--
+		default:
+			b.WriteRune('_')
+		}
+		b.WriteRune(' ')
+	}
+
+	return strings.TrimSpace(b.String())
+}
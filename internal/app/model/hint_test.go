@@ -0,0 +1,61 @@
+package model
+
+import "testing"
+
+func TestQuestionPoints(t *testing.T) {
+	testCases := []struct {
+		hints int
+		want  int
+	}{
+		{0, MaxPoints},
+		{1, MaxPoints - 1},
+		{MaxPoints - 1, 1},
+		{MaxPoints + 3, 1},
+	}
+
+	for _, tc := range testCases {
+		if got := QuestionPoints(tc.hints); got != tc.want {
+			t.Errorf("QuestionPoints(%d) = %d, want %d", tc.hints, got, tc.want)
+		}
+	}
+}
+
+func TestMaskAnswer(t *testing.T) {
+	testCases := []struct {
+		answer   string
+		revealed int
+		want     string
+	}{
+		{"Кот", 0, "_ _ _"},
+		{"Кот", 1, "К _ _"},
+		{"Лев Толстой", 4, "Л е в   Т _ _ _ _ _ _"},
+		{"A-1", 1, "A - _"},
+		{"Ёж", 5, "Ё ж"},
+	}
+
+	for _, tc := range testCases {
+		q := &Question{Answer: tc.answer}
+		if got := q.MaskAnswer(tc.revealed); got != tc.want {
+			t.Errorf("MaskAnswer(%q, %d) = %q, want %q", tc.answer, tc.revealed, got, tc.want)
+		}
+	}
+}
+
+func TestMaxHints(t *testing.T) {
+	testCases := []struct {
+		answer string
+		want   int
+	}{
+		{"Кот", 3},
+		{"Лев Толстой", 10},
+		{"1984", 4},
+		{"«Мастер и Маргарита»", 16},
+	}
+
+	for _, tc := range testCases {
+		q := &Question{Answer: tc.answer}
+		if got := q.MaxHints(); got != tc.want {
+			t.Errorf("MaxHints(%q) = %d, want %d", tc.answer, got, tc.want)
+		}
+	}
+}
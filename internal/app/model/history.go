@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+//HistoryEntry is a question received by a user
+type HistoryEntry struct {
+	Question *Question
+	Correct  bool
+	AskedAt  time.Time
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+//Types of media attached to questions
+const (
+	MediaPhoto    = "photo"
+	MediaAudio    = "audio"
+	MediaDocument = "document"
+)
+
+//Formats of question texts, the question, the answer and the comment of a question share the format
+const (
+	FormatPlain    = ""
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+//Media is a file attached to a question, e.g. a picture of a handout.
+//It is given by URL or by a path of a local file
+type Media struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+	File string `json:"file"`
+}
+
+//ParseMode returns the telegram parse mode of the question's texts
+func (q *Question) ParseMode() string {
+	switch q.Format {
+	case FormatHTML:
+		return tgbotapi.ModeHTML
+	case FormatMarkdown:
+		return tgbotapi.ModeMarkdown
+	}
+
+	return ""
+}
+
+var (
+	htmlEscaper     = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+)
+
+//Escape makes a plain text safe to be put together with the question's formatted texts
+func (q *Question) Escape(text string) string {
+	switch q.Format {
+	case FormatHTML:
+		return htmlEscaper.Replace(text)
+	case FormatMarkdown:
+		return markdownEscaper.Replace(text)
+	}
+
+	return text
+}
+
+var (
+	htmlTags      = regexp.MustCompile(`<[^>]*>`)
+	markdownMarks = regexp.MustCompile("\\\\([_*`\\[])|[_*`]")
+)
+
+//HTML returns a text of the question as HTML shortened to max runes, so that texts of questions
+//of different formats can be put together in one message. Markdown and shortened texts lose their formatting
+func (q *Question) HTML(text string, max int) string {
+	if q.Format == FormatHTML && utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	switch q.Format {
+	case FormatHTML:
+		text = html.UnescapeString(htmlTags.ReplaceAllString(text, ""))
+	case FormatMarkdown:
+		text = markdownMarks.ReplaceAllString(text, "$1")
+	}
+
+	if runes := []rune(text); len(runes) > max {
+		text = string(runes[:max-1]) + "…"
+	}
+
+	return htmlEscaper.Replace(text)
+}
@@ -1,35 +1,55 @@
 package model
 
 import (
-	"fmt"
+	"qask_telegram/internal/app/locale"
+	"strconv"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
+//TimerOptions are time limits of a question in seconds a user can choose, 0 turns the timer off
+var TimerOptions = []int{0, 30, 60, 90, 120}
+
 //Message is a structure, that allow to use a multi-level editable message
 type Message struct {
 	Msg  tgbotapi.Chattable
 	Prev *Message
 }
 
-//PlayMessage is a first-level game editable message
-func PlayMessage(user *User) *Message {
+//PlayMessage is a first-level game editable message, dueReviews is the number of questions due for review
+func PlayMessage(user *User, dueReviews int) *Message {
 	var keyboardMarkup = make([][]tgbotapi.InlineKeyboardButton, 0)
 
 	if user.QuestSubscribtion == true {
-		btnGetQuestion := tgbotapi.NewInlineKeyboardButtonData("Случайный вопрос", "/getQuestion")
+		btnGetQuestion := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.random_question"), "/getQuestion")
 		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnGetQuestion))
+
+		if user.Session != nil && user.Session.Paused {
+			btnResume := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.resume_session", user.Session.Played(), user.Session.Length), "/resumeSession")
+			keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnResume))
+		}
+
+		btnSession := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.session", SessionLength), "/startSession")
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnSession))
+
+		btnReview := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.review", dueReviews), "/review")
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnReview))
+	}
+
+	if user.TeamID != 0 {
+		btnTeam := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.team"), "/team")
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnTeam))
 	}
 
 	if user.MathProblemSubscribtion == true {
-		btnGetMathProblem := tgbotapi.NewInlineKeyboardButtonData("Математическая задача", "/getMathProblem")
+		btnGetMathProblem := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.math_problem"), "/getMathProblem")
 		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnGetMathProblem))
 	}
 
-	btnSettings := tgbotapi.NewInlineKeyboardButtonData("Настройки игры", "/settings")
+	btnSettings := tgbotapi.NewInlineKeyboardButtonData(user.Tr("play.settings"), "/settings")
 	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnSettings))
 
-	msg := tgbotapi.NewMessage(user.UserID(), "Выберите действие:")
+	msg := tgbotapi.NewMessage(user.UserID(), user.Tr("play.title"))
 	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
 
 	newMessage := &Message{
@@ -42,15 +62,31 @@ func PlayMessage(user *User) *Message {
 
 //GameMainSettingsMessage ...
 func GameMainSettingsMessage(user *User) *Message {
-	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, "Настройки игры")
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, user.Tr("settings.title"))
 
-	btn1 := tgbotapi.NewInlineKeyboardButtonData("Подписки", "/subscribtions")
+	btn1 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("settings.subscriptions"), "/subscribtions")
 	btn1Row := tgbotapi.NewInlineKeyboardRow(btn1)
 
-	btnBack := tgbotapi.NewInlineKeyboardButtonData("<< назад", "/back")
+	btn2 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("settings.timer", TimerLabel(user, user.Timer)), "/timer")
+	btn2Row := tgbotapi.NewInlineKeyboardRow(btn2)
+
+	categories := user.Tr("categories.all")
+	if len(user.Categories) != 0 {
+		categories = locale.Plural(user.Lang(), "categories", len(user.Categories), len(user.Categories))
+	}
+	btn3 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("settings.categories", categories), "/categories")
+	btn3Row := tgbotapi.NewInlineKeyboardRow(btn3)
+
+	btn4 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("settings.difficulty", DifficultyLabel(user, user.Difficulty)), "/difficulty")
+	btn4Row := tgbotapi.NewInlineKeyboardRow(btn4)
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData(user.Tr("button.back"), "/back")
 	btnBackRow := tgbotapi.NewInlineKeyboardRow(btnBack)
 
-	rows := tgbotapi.NewInlineKeyboardMarkup(btn1Row, btnBackRow)
+	btn5 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("settings.reset_history"), "/resetHistory")
+	btn5Row := tgbotapi.NewInlineKeyboardRow(btn5)
+
+	rows := tgbotapi.NewInlineKeyboardMarkup(btn1Row, btn2Row, btn3Row, btn4Row, btn5Row, btnBackRow)
 
 	msg.ReplyMarkup = &rows
 
@@ -61,15 +97,15 @@ func GameMainSettingsMessage(user *User) *Message {
 
 //GameSubscriptionsSettingsMessage ...
 func GameSubscriptionsSettingsMessage(user *User) *Message {
-	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, "Настройки подписок")
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, user.Tr("subscriptions.title"))
 
-	btn1 := tgbotapi.NewInlineKeyboardButtonData("Получать вопросы", "/subscribeQuestions")
+	btn1 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("subscriptions.questions"), "/subscribeQuestions")
 	btn1Row := tgbotapi.NewInlineKeyboardRow(btn1)
 
-	btn2 := tgbotapi.NewInlineKeyboardButtonData("Получать математические задачи", "/subscribeMathProblems")
+	btn2 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("subscriptions.math_problems"), "/subscribeMathProblems")
 	btn2Row := tgbotapi.NewInlineKeyboardRow(btn2)
 
-	btnBack := tgbotapi.NewInlineKeyboardButtonData("<< назад", "/back")
+	btnBack := tgbotapi.NewInlineKeyboardButtonData(user.Tr("button.back"), "/back")
 	btnBackRow := tgbotapi.NewInlineKeyboardRow(btnBack)
 
 	rows := tgbotapi.NewInlineKeyboardMarkup(btn1Row, btn2Row, btnBackRow)
@@ -81,19 +117,134 @@ func GameSubscriptionsSettingsMessage(user *User) *Message {
 	}
 }
 
+//TimerLabel is a time limit of a question as shown to the user
+func TimerLabel(user *User, seconds int) string {
+	if seconds == 0 {
+		return user.Tr("timer.off")
+	}
+
+	return user.Tr("timer.seconds", seconds)
+}
+
+//GameTimerSettingsMessage is a game settings message with a list of question time limits
+func GameTimerSettingsMessage(user *User) *Message {
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, user.Tr("timer.title"))
+
+	var keyboardMarkup = make([][]tgbotapi.InlineKeyboardButton, 0)
+	for _, seconds := range TimerOptions {
+		label := TimerLabel(user, seconds)
+		if seconds == user.Timer {
+			label = "✅ " + label
+		}
+
+		btnTimer := tgbotapi.NewInlineKeyboardButtonData(label, "/setTimer "+strconv.Itoa(seconds))
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnTimer))
+	}
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData(user.Tr("button.back"), "/back")
+	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	rows := tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
+	msg.ReplyMarkup = &rows
+
+	return &Message{
+		Msg: &msg,
+	}
+}
+
+//GameCategoriesSettingsMessage is a game settings message with a list of categories, chosen ones are checked
+func GameCategoriesSettingsMessage(user *User, categories []string) *Message {
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, user.Tr("categories.title"))
+
+	var keyboardMarkup = make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	label := user.Tr("categories.all")
+	if len(user.Categories) == 0 {
+		label = "✅ " + label
+	}
+	btnAll := tgbotapi.NewInlineKeyboardButtonData(label, "/toggleCategory all")
+	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnAll))
+
+	// Categories are referred by index, names may not fit into callback data
+	for i, category := range categories {
+		label := category
+		if user.PrefersCategory(category) {
+			label = "✅ " + label
+		}
+
+		btnCategory := tgbotapi.NewInlineKeyboardButtonData(label, "/toggleCategory "+strconv.Itoa(i))
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnCategory))
+	}
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData(user.Tr("button.back"), "/back")
+	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	rows := tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
+	msg.ReplyMarkup = &rows
+
+	return &Message{
+		Msg: &msg,
+	}
+}
+
+//DifficultyLabel is a difficulty level as shown to the user
+func DifficultyLabel(user *User, difficulty int) string {
+	return user.Tr("difficulty." + strconv.Itoa(difficulty))
+}
+
+//GameDifficultySettingsMessage is a game settings message with a list of difficulty levels
+func GameDifficultySettingsMessage(user *User) *Message {
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.PlayMessage.MessageID, user.Tr("difficulty.title"))
+
+	var keyboardMarkup = make([][]tgbotapi.InlineKeyboardButton, 0)
+	for _, difficulty := range Difficulties {
+		label := DifficultyLabel(user, difficulty)
+		if difficulty == user.Difficulty {
+			label = "✅ " + label
+		}
+
+		btnDifficulty := tgbotapi.NewInlineKeyboardButtonData(label, "/setDifficulty "+strconv.Itoa(difficulty))
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnDifficulty))
+	}
+
+	btnBack := tgbotapi.NewInlineKeyboardButtonData(user.Tr("button.back"), "/back")
+	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnBack))
+
+	rows := tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
+	msg.ReplyMarkup = &rows
+
+	return &Message{
+		Msg: &msg,
+	}
+}
+
 // ProfileMain ...
-func ProfileMain(user *User) *Message {
-	msgProfile := fmt.Sprintf("Настройки профиля")
+func ProfileMain(user *User, referrals int, team string) *Message {
+	msgProfile := user.Tr("profile.title") + "\n" + user.Tr("profile.referrals", referrals)
 
-	strSetFirstName := fmt.Sprintf("Имя [%s]", user.FirstName)
+	strSetFirstName := user.Tr("profile.first_name", user.FirstName)
 	btnSetFirstName := tgbotapi.NewInlineKeyboardButtonData(strSetFirstName, "/setFirstName")
 	btnSetFirstNameRow := tgbotapi.NewInlineKeyboardRow(btnSetFirstName)
 
-	strSetUserName := fmt.Sprintf("Имя пользователя [%s]", user.UserName)
+	strSetUserName := user.Tr("profile.user_name", user.UserName)
 	btnSetUserName := tgbotapi.NewInlineKeyboardButtonData(strSetUserName, "/setUserName")
 	btnSetUserNameRow := tgbotapi.NewInlineKeyboardRow(btnSetUserName)
 
-	msgProfileKeyboardMarkup := tgbotapi.NewInlineKeyboardMarkup(btnSetFirstNameRow, btnSetUserNameRow)
+	language := user.Tr("language.auto")
+	if user.Language != "" {
+		language = user.Tr("language." + user.Language)
+	}
+	strSetLanguage := user.Tr("profile.language", language)
+	btnSetLanguage := tgbotapi.NewInlineKeyboardButtonData(strSetLanguage, "/language")
+	btnSetLanguageRow := tgbotapi.NewInlineKeyboardRow(btnSetLanguage)
+
+	if team == "" {
+		team = user.Tr("profile.no_team")
+	}
+	btnTeam := tgbotapi.NewInlineKeyboardButtonData(user.Tr("profile.team", team), "/team")
+	btnTeamRow := tgbotapi.NewInlineKeyboardRow(btnTeam)
+
+	msgProfileKeyboardMarkup := tgbotapi.NewInlineKeyboardMarkup(btnSetFirstNameRow, btnSetUserNameRow, btnSetLanguageRow, btnTeamRow)
 
 	msg := tgbotapi.NewMessage(int64(user.UserId), msgProfile)
 	msg.ReplyMarkup = msgProfileKeyboardMarkup
@@ -106,14 +257,12 @@ func ProfileMain(user *User) *Message {
 
 //WelcomeMessage is a "start" message a user recieves when sending message "/start"
 func WelcomeMessage(user *User) *Message {
-	msgWelcome := fmt.Sprintf(
-		`Добро пожаловать, %s!
-Для игры необходимо зарегистрироваться. Попробуй сделать это прямо сейчас, нажав кнопку "Зарегистрироваться".`, user.FirstName)
+	msgWelcome := user.Tr("welcome.text", user.FirstName)
 
-	btnRegister := tgbotapi.NewInlineKeyboardButtonData("Зарегистрироваться", "/register")
+	btnRegister := tgbotapi.NewInlineKeyboardButtonData(user.Tr("welcome.register"), "/register")
 	btnRegisterRow := tgbotapi.NewInlineKeyboardRow(btnRegister)
 
-	btnProfileSettings := tgbotapi.NewInlineKeyboardButtonData("Настройки профиля", "/profile")
+	btnProfileSettings := tgbotapi.NewInlineKeyboardButtonData(user.Tr("profile.title"), "/profile")
 	btnProfileSettingsRow := tgbotapi.NewInlineKeyboardRow(btnProfileSettings)
 
 	msgWelcomeKeyboardMarkup := tgbotapi.NewInlineKeyboardMarkup(btnRegisterRow, btnProfileSettingsRow)
@@ -129,9 +278,9 @@ func WelcomeMessage(user *User) *Message {
 
 //WelcomeMessageAfterRegister ...
 func WelcomeMessageAfterRegister(user *User) *Message {
-	msg := tgbotapi.NewEditMessageText(user.UserID(), user.WelcomeMessage.MessageID, "Регистрация прошла успешно")
+	msg := tgbotapi.NewEditMessageText(user.UserID(), user.WelcomeMessage.MessageID, user.Tr("welcome.registered"))
 
-	btn1 := tgbotapi.NewInlineKeyboardButtonData("Настройки профиля", "/profile")
+	btn1 := tgbotapi.NewInlineKeyboardButtonData(user.Tr("profile.title"), "/profile")
 	btn1Row := tgbotapi.NewInlineKeyboardRow(btn1)
 
 	rows := tgbotapi.NewInlineKeyboardMarkup(btn1Row)
@@ -142,3 +291,24 @@ func WelcomeMessageAfterRegister(user *User) *Message {
 		Msg: &msg,
 	}
 }
+
+//LanguageMessage is a profile message with a list of available languages
+func LanguageMessage(user *User) *Message {
+	var keyboardMarkup = make([][]tgbotapi.InlineKeyboardButton, 0)
+
+	btnAuto := tgbotapi.NewInlineKeyboardButtonData(user.Tr("language.auto"), "/setLanguage auto")
+	keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnAuto))
+
+	for _, lang := range locale.Languages() {
+		btnLanguage := tgbotapi.NewInlineKeyboardButtonData(locale.Get(lang, "language."+lang), "/setLanguage "+lang)
+		keyboardMarkup = append(keyboardMarkup, tgbotapi.NewInlineKeyboardRow(btnLanguage))
+	}
+
+	msg := tgbotapi.NewMessage(user.UserID(), user.Tr("profile.choose_language"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(keyboardMarkup...)
+
+	return &Message{
+		Msg:  &msg,
+		Prev: nil,
+	}
+}
@@ -1,49 +1,110 @@
 package model
 
 import (
-	"bytes"
-	"encoding/json"
-	"io/ioutil"
-	"net/http"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type Question struct {
-	Question string `json:"question"`
-	Answer   string `json:"answer"`
-	Comment  string `json:"comment"`
+	ID            int      `json:"id"`
+	Question      string   `json:"question"`
+	Answer        string   `json:"answer"`
+	Comment       string   `json:"comment"`
+	Category      string   `json:"category"`
+	Tags          []string `json:"tags"`
+	Difficulty    int      `json:"difficulty"`
+	Source        string   `json:"source"`
+	Author        string   `json:"author"`
+	Format        string   `json:"format"`
+	Media         *Media   `json:"media"`
+	Options       []string `json:"options"`
+	CorrectOption int      `json:"correct_option"`
 }
 
-func GetQuestion(user *User) *Question {
-	type request struct {
-		TgId int64  `json:"tgId"`
-		From string `json:"from"`
-	}
-	re := &request{}
-	re.TgId = user.UserID()
-	re.From = "telegram"
+//Limits of telegram quiz polls on the options of a multiple-choice question
+const (
+	MinOptions      = 2
+	MaxOptions      = 10
+	MaxOptionLength = 100
+)
 
-	b := &bytes.Buffer{}
-	json.NewEncoder(b).Encode(re)
-	req, _ := http.NewRequest(http.MethodGet, "http://172.20.0.3:30001/questions", b)
+//Difficulty levels of questions, DifficultyAny means questions of any difficulty
+const (
+	DifficultyAny = iota
+	DifficultyEasy
+	DifficultyMedium
+	DifficultyHard
+)
 
-	client := http.Client{}
+//Difficulties are difficulty levels a user can choose
+var Difficulties = []int{DifficultyAny, DifficultyEasy, DifficultyMedium, DifficultyHard}
 
-	resp, err := client.Do(req)
+//QuestionFilter is a user's preferences sent with a question request, empty values mean any question.
+//Search limits questions to the ones containing the text
+type QuestionFilter struct {
+	Categories []string
+	Difficulty int
+	Exclude    []int
+	Search     string
+}
 
-	if err != nil {
-		return nil
+//IsQuiz reports whether the question is a multiple-choice one which can be sent as a quiz poll
+func (q *Question) IsQuiz() bool {
+	if len(q.Options) < MinOptions || len(q.Options) > MaxOptions {
+		return false
+	}
+	if q.CorrectOption < 0 || q.CorrectOption >= len(q.Options) {
+		return false
 	}
 
-	defer resp.Body.Close()
-
-	data, _ := ioutil.ReadAll(resp.Body)
+	for _, option := range q.Options {
+		if option == "" || utf8.RuneCountInString(option) > MaxOptionLength {
+			return false
+		}
+	}
 
-	var q Question
+	return true
+}
 
-	err = json.Unmarshal(data, &q)
-	if err != nil {
+//SetOptions makes the question a multiple-choice one, the answer must be one of the options.
+//A question without an answer gets the option with the index CorrectOption as the answer
+func (q *Question) SetOptions(options []string) error {
+	q.Options = options
+	if q.Answer == "" {
+		if q.CorrectOption < 0 || q.CorrectOption >= len(options) {
+			return errors.New("the correct option is out of range")
+		}
+		q.Answer = options[q.CorrectOption]
 		return nil
 	}
 
-	return &q
+	for i, option := range options {
+		if q.CheckAnswer(option) {
+			q.CorrectOption = i
+			return nil
+		}
+	}
+
+	return errors.New("the answer is not one of the options")
+}
+
+//CheckAnswer compares the answer ignoring case, punctuation and extra spaces
+func (q *Question) CheckAnswer(answer string) bool {
+	return normalizeAnswer(answer) == normalizeAnswer(q.Answer)
+}
+
+func normalizeAnswer(answer string) string {
+	answer = strings.ToLower(answer)
+	answer = strings.Replace(answer, "ё", "е", -1)
+
+	answer = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return ' '
+		}
+		return r
+	}, answer)
+
+	return strings.Join(strings.Fields(answer), " ")
 }
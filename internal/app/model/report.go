@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+//Report is a problem reported by a user, e.g. a wrong answer to a question
+type Report struct {
+	ID        int
+	UserID    int
+	Question  *Question
+	Text      string
+	CreatedAt time.Time
+}
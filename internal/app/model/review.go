@@ -0,0 +1,71 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+//ReviewGrade is a user's own assessment of how well the answer was recalled
+type ReviewGrade int
+
+//Grades of the SM-2 algorithm a user can choose, grades below ReviewHard mean the answer was forgotten
+const (
+	ReviewForgot ReviewGrade = 1
+	ReviewHard   ReviewGrade = 3
+	ReviewEasy   ReviewGrade = 5
+)
+
+const (
+	initialEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+//ReviewCard is a question scheduled for review with the SM-2 algorithm
+type ReviewCard struct {
+	Question    *Question
+	EaseFactor  float64
+	Interval    int
+	Repetitions int
+	Due         time.Time
+}
+
+//NewReviewCard creates a card of the question due immediately
+func NewReviewCard(q *Question) *ReviewCard {
+	return &ReviewCard{
+		Question:   q,
+		EaseFactor: initialEaseFactor,
+		Due:        time.Now(),
+	}
+}
+
+//IsDue reports whether the card should be reviewed at the moment
+func (c *ReviewCard) IsDue(now time.Time) bool {
+	return !now.Before(c.Due)
+}
+
+//Grade schedules the next review of the card according to the grade
+func (c *ReviewCard) Grade(grade ReviewGrade, now time.Time) {
+	q := float64(grade)
+
+	if grade < ReviewHard {
+		c.Repetitions = 0
+		c.Interval = 1
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.EaseFactor))
+		}
+		c.Repetitions++
+	}
+
+	c.EaseFactor += 0.1 - (5-q)*(0.08+(5-q)*0.02)
+	if c.EaseFactor < minEaseFactor {
+		c.EaseFactor = minEaseFactor
+	}
+
+	c.Due = now.AddDate(0, 0, c.Interval)
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReviewCardGrade(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	card := NewReviewCard(&Question{})
+
+	steps := []struct {
+		grade       ReviewGrade
+		interval    int
+		repetitions int
+		easeFactor  float64
+	}{
+		{ReviewEasy, 1, 1, 2.6},
+		{ReviewEasy, 6, 2, 2.7},
+		{ReviewHard, 16, 3, 2.56},
+		{ReviewForgot, 1, 0, 2.02},
+		{ReviewHard, 1, 1, 1.88},
+	}
+
+	for i, step := range steps {
+		card.Grade(step.grade, now)
+
+		if card.Interval != step.interval || card.Repetitions != step.repetitions {
+			t.Errorf("step %d: interval %d, repetitions %d, want %d, %d", i+1, card.Interval, card.Repetitions, step.interval, step.repetitions)
+		}
+		if math.Abs(card.EaseFactor-step.easeFactor) > 1e-9 {
+			t.Errorf("step %d: ease factor %.2f, want %.2f", i+1, card.EaseFactor, step.easeFactor)
+		}
+		if want := now.AddDate(0, 0, step.interval); !card.Due.Equal(want) {
+			t.Errorf("step %d: due %s, want %s", i+1, card.Due, want)
+		}
+	}
+}
+
+func TestReviewCardMinEaseFactor(t *testing.T) {
+	card := NewReviewCard(&Question{})
+	for i := 0; i < 10; i++ {
+		card.Grade(ReviewForgot, time.Now())
+	}
+
+	if card.EaseFactor != minEaseFactor {
+		t.Errorf("ease factor %.2f, want %.2f", card.EaseFactor, minEaseFactor)
+	}
+}
+
+func TestReviewCardIsDue(t *testing.T) {
+	card := NewReviewCard(&Question{})
+	now := time.Now()
+	if !card.IsDue(now) {
+		t.Error("a new card is not due")
+	}
+
+	card.Grade(ReviewEasy, now)
+	if card.IsDue(now) {
+		t.Error("a graded card is due at once")
+	}
+	if !card.IsDue(now.AddDate(0, 0, 1)) {
+		t.Error("a card is not due after its interval")
+	}
+}
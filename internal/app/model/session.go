@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+//SessionLength is a number of questions in a game session
+const SessionLength = 10
+
+//SessionResult is an outcome of a question of a session
+type SessionResult int
+
+const (
+	//SessionCorrect is a question answered correctly
+	SessionCorrect SessionResult = iota
+	//SessionMissed is a question whose answer was shown or whose time ran out
+	SessionMissed
+	//SessionSkipped is a question skipped by the user
+	SessionSkipped
+)
+
+//Session is a fixed-length series of questions, it can be paused and resumed later
+type Session struct {
+	Length          int
+	Number          int
+	Correct         int
+	Missed          int
+	Skipped         int
+	Paused          bool
+	Question        *Question
+	StartedAt       time.Time
+	Elapsed         time.Duration
+	questionStarted time.Time
+}
+
+//NewSession creates a session of length questions
+func NewSession(length int) *Session {
+	return &Session{
+		Length:    length,
+		StartedAt: time.Now(),
+	}
+}
+
+//NextQuestion makes the question the current one
+func (s *Session) NextQuestion(q *Question) {
+	s.Number++
+	s.Question = q
+	s.questionStarted = time.Now()
+}
+
+//Record counts the result of the current question, it reports whether there was an open question
+func (s *Session) Record(result SessionResult) bool {
+	if s.Question == nil {
+		return false
+	}
+
+	s.Elapsed += time.Since(s.questionStarted)
+	s.Question = nil
+
+	switch result {
+	case SessionCorrect:
+		s.Correct++
+	case SessionMissed:
+		s.Missed++
+	case SessionSkipped:
+		s.Skipped++
+	}
+
+	return true
+}
+
+//Pause stops counting the time of the current question
+func (s *Session) Pause() {
+	if s.Question != nil {
+		s.Elapsed += time.Since(s.questionStarted)
+	}
+	s.Paused = true
+}
+
+//Resume continues the session after a pause
+func (s *Session) Resume() {
+	s.Paused = false
+	s.questionStarted = time.Now()
+}
+
+//Finished reports whether all questions of the session have been played
+func (s *Session) Finished() bool {
+	return s.Played() >= s.Length
+}
+
+//Played returns the number of questions with a result
+func (s *Session) Played() int {
+	return s.Correct + s.Missed + s.Skipped
+}
+
+//AverageTime returns the average time spent on a question
+func (s *Session) AverageTime() time.Duration {
+	if s.Played() == 0 {
+		return 0
+	}
+
+	return s.Elapsed / time.Duration(s.Played())
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+//Statuses of a submitted question
+const (
+	SubmissionPending    = "pending"
+	SubmissionPublishing = "publishing"
+	SubmissionApproved   = "approved"
+	SubmissionRejected   = "rejected"
+)
+
+//Limits of the texts of a submitted question in characters
+const (
+	MinSubmissionQuestion = 10
+	MaxSubmissionQuestion = 1000
+	MaxSubmissionAnswer   = 100
+	MaxSubmissionComment  = 1000
+	MaxSubmissionSource   = 300
+)
+
+//Submission is a question proposed by a user, it is published in qask after an admin approves it
+type Submission struct {
+	ID         int
+	UserID     int
+	Question   *Question
+	Status     string
+	Reason     string
+	ReviewedBy int
+	CreatedAt  time.Time
+}
+
+//SubmissionFieldLength returns the limits of a field of a submitted question,
+//the minimum is 0 for optional fields
+func SubmissionFieldLength(field string) (min int, max int) {
+	switch field {
+	case "question":
+		return MinSubmissionQuestion, MaxSubmissionQuestion
+	case "answer":
+		return 1, MaxSubmissionAnswer
+	case "comment":
+		return 0, MaxSubmissionComment
+	case "source":
+		return 0, MaxSubmissionSource
+	}
+
+	return 0, 0
+}
+
+//ValidSubmissionField reports whether the text fits the limits of the field
+func ValidSubmissionField(field string, text string) bool {
+	min, max := SubmissionFieldLength(field)
+	length := utf8.RuneCountInString(text)
+
+	return length >= min && length <= max
+}
+
+//Field returns a text field of the question by its name
+func (q *Question) Field(field string) string {
+	switch field {
+	case "question":
+		return q.Question
+	case "answer":
+		return q.Answer
+	case "comment":
+		return q.Comment
+	case "source":
+		return q.Source
+	}
+
+	return ""
+}
+
+//SetField sets a text field of the question by its name
+func (q *Question) SetField(field string, text string) {
+	switch field {
+	case "question":
+		q.Question = text
+	case "answer":
+		q.Answer = text
+	case "comment":
+		q.Comment = text
+	case "source":
+		q.Source = text
+	}
+}
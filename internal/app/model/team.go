@@ -0,0 +1,162 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+//Limits of team names in characters and of team sizes
+const (
+	MinTeamName    = 2
+	MaxTeamName    = 32
+	MaxTeamMembers = 10
+)
+
+//Team is a group of users who answer questions together, only the captain submits the team's answer.
+//Users join a team by its invite code
+type Team struct {
+	mu               sync.Mutex
+	ID               int
+	Name             string
+	Code             string
+	CaptainID        int
+	Members          []int
+	Question         *Question
+	QuestionAnswered bool
+	CreatedAt        time.Time
+}
+
+//TeamResult is the result of a team on a question
+type TeamResult struct {
+	TeamID     int
+	QuestionID int
+	Correct    bool
+	Points     int
+	AnsweredAt time.Time
+}
+
+//TeamStats are the results of a team on all its questions
+type TeamStats struct {
+	TeamID  int
+	Played  int
+	Correct int
+	Points  int
+}
+
+//IsMember reports whether the user is a member of the team
+func (t *Team) IsMember(userID int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.isMember(userID)
+}
+
+func (t *Team) isMember(userID int) bool {
+	for _, id := range t.Members {
+		if id == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+//IsCaptain reports whether the user is the captain of the team
+func (t *Team) IsCaptain(userID int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.CaptainID == userID
+}
+
+//MemberIDs returns a copy of the IDs of the members
+func (t *Team) MemberIDs() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]int(nil), t.Members...)
+}
+
+//AddMember adds the user to the team, it reports false if the team is full
+func (t *Team) AddMember(userID int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.isMember(userID) {
+		return true
+	}
+	if len(t.Members) >= MaxTeamMembers {
+		return false
+	}
+
+	t.Members = append(t.Members, userID)
+	return true
+}
+
+//RemoveMember removes the user from the team, the captain passes the role to the member who joined first.
+//It reports whether the team has no members left
+func (t *Team) RemoveMember(userID int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, id := range t.Members {
+		if id == userID {
+			t.Members = append(t.Members[:i:i], t.Members[i+1:]...)
+			break
+		}
+	}
+
+	if len(t.Members) == 0 {
+		return true
+	}
+
+	if t.CaptainID == userID {
+		t.CaptainID = t.Members[0]
+	}
+
+	return false
+}
+
+//SetQuestion makes the question the current question of the team
+func (t *Team) SetQuestion(q *Question) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.Question = q
+	t.QuestionAnswered = false
+}
+
+//OpenQuestion returns the current question of the team if it is not answered yet
+func (t *Team) OpenQuestion() *Question {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.QuestionAnswered {
+		return nil
+	}
+
+	return t.Question
+}
+
+//CloseQuestion marks the question answered if it is still the open question of the team,
+//only the first call for a question succeeds
+func (t *Team) CloseQuestion(q *Question) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if q == nil || t.Question != q || t.QuestionAnswered {
+		return false
+	}
+
+	t.QuestionAnswered = true
+	return true
+}
+
+//Record adds the result of a question to the stats
+func (s *TeamStats) Record(result *TeamResult) {
+	s.Played++
+	if result.Correct {
+		s.Correct++
+	}
+	s.Points += result.Points
+}
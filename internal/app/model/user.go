@@ -3,20 +3,36 @@ package model
 import (
 	"errors"
 	"github.com/go-telegram-bot-api/telegram-bot-api"
+	"qask_telegram/internal/app/locale"
+	"sync"
+	"time"
 )
 
 type userPublic struct {
 	FirstName string `json:"firstName"`
 	UserName  string `json:"userName"`
+	Language  string `json:"language"`
 }
 
 type userPrivate struct {
 	DBID                    int  `json:"dbId"`
 	UserId                  int  `json:"UserId"`
 	Registered              bool `json:"registered"`
+	Banned                  bool `json:"banned"`
+	Inactive                bool `json:"inactive"`
 	State                   int  `json:"state"`
+	LanguageCode            string
+	CreatedAt               time.Time
+	RegisteredAt            time.Time
+	ReferrerID              int
+	TeamID                  int
+	LastSeen                time.Time
 	QuestSubscribtion       bool
 	MathProblemSubscribtion bool
+	Timer                   int
+	Points                  int
+	Categories              []string
+	Difficulty              int
 	WelcomeMessage          tgbotapi.Message
 	WelcomeMessageHead      *Message
 	ProfileMessage          tgbotapi.Message
@@ -24,11 +40,24 @@ type userPrivate struct {
 	PlayMessage             tgbotapi.Message
 	PlayMessageHead         *Message
 	QuestionMessage         tgbotapi.Message
+	QuestionPoll            string
 	Question                *Question
+	QuestionAnswered        bool
+	Hints                   int
+	HistoryEntry            *HistoryEntry
+	ReviewCard              *ReviewCard
+	QuestionDeadline        time.Time
+	TimerStop               func()
+	Session                 *Session
+	SharedQuestion          *Question
 	WriteTo                 *string
+	OnText                  func(string)
+	SubmissionDraft         *Question
 }
 
 type User struct {
+	//mu guards the state of the current question which the countdown shares with handlers
+	mu sync.Mutex
 	userPublic
 	userPrivate
 }
@@ -51,3 +80,152 @@ func (u *User) IsRegistered() bool {
 func (u *User) UserID() int64 {
 	return int64(u.UserId)
 }
+
+//Seen updates the user's data from the latest update sent by the user.
+//A user who has blocked the bot becomes active again by writing to it
+func (u *User) Seen(from *tgbotapi.User) {
+	u.LanguageCode = from.LanguageCode
+	u.LastSeen = time.Now()
+	u.Inactive = false
+}
+
+//TimeIsUp reports whether the time limit of the current question has expired
+func (u *User) TimeIsUp() bool {
+	return !u.QuestionDeadline.IsZero() && time.Now().After(u.QuestionDeadline)
+}
+
+//QuestionFilter returns the user's preferred categories and difficulty of questions
+func (u *User) QuestionFilter() QuestionFilter {
+	return QuestionFilter{
+		Categories: u.Categories,
+		Difficulty: u.Difficulty,
+	}
+}
+
+//ToggleCategory adds the category to the preferred ones or removes it
+func (u *User) ToggleCategory(category string) {
+	for i, c := range u.Categories {
+		if c == category {
+			u.Categories = append(u.Categories[:i:i], u.Categories[i+1:]...)
+			return
+		}
+	}
+
+	u.Categories = append(u.Categories, category)
+}
+
+//PrefersCategory reports whether the category is one of the preferred ones
+func (u *User) PrefersCategory(category string) bool {
+	for _, c := range u.Categories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+//InSession reports whether the user plays a session which is not paused
+func (u *User) InSession() bool {
+	return u.Session != nil && !u.Session.Paused
+}
+
+//SessionQuestion reports whether the current question is the open question of the user's session
+func (u *User) SessionQuestion() bool {
+	return u.InSession() && u.Question != nil && u.Session.Question == u.Question
+}
+
+//StopTimer stops the countdown of the current question if there is one
+func (u *User) StopTimer() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.stopTimer()
+}
+
+func (u *User) stopTimer() {
+	if u.TimerStop != nil {
+		u.TimerStop()
+		u.TimerStop = nil
+	}
+}
+
+//StartQuestion makes the question the current unanswered question, the deadline is set by the user's timer
+func (u *User) StartQuestion(q *Question) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.stopTimer()
+	u.Question = q
+	u.QuestionAnswered = false
+	u.QuestionPoll = ""
+	u.Hints = 0
+	u.QuestionDeadline = time.Time{}
+	if u.Timer > 0 {
+		u.QuestionDeadline = time.Now().Add(time.Duration(u.Timer) * time.Second)
+	}
+}
+
+//StartTimer sets the function which stops the countdown of the current question
+func (u *User) StartTimer(stop func()) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.TimerStop = stop
+}
+
+//IfQuestionOpen calls f with the lock held if the question is still the current unanswered question,
+//it reports whether f was called
+func (u *User) IfQuestionOpen(q *Question, f func()) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.Question != q || u.QuestionAnswered {
+		return false
+	}
+
+	f()
+	return true
+}
+
+//CloseQuestion marks the question answered if it is still the current unanswered question,
+//only the first call for a question succeeds
+func (u *User) CloseQuestion(q *Question) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.Question != q || u.QuestionAnswered {
+		return false
+	}
+
+	u.QuestionAnswered = true
+	return true
+}
+
+//AnswerQuestion marks the current question answered and stops its countdown
+func (u *User) AnswerQuestion() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.stopTimer()
+	u.QuestionAnswered = true
+}
+
+//Lang returns the language chosen by the user in the profile or,
+//if there is none, the language of the user's telegram client
+func (u *User) Lang() string {
+	if u == nil {
+		return locale.DefaultLanguage
+	}
+
+	if u.Language != "" {
+		return u.Language
+	}
+
+	return locale.Normalize(u.LanguageCode)
+}
+
+//Tr returns the message translated to the user's language
+func (u *User) Tr(key string, args ...interface{}) string {
+	return locale.Get(u.Lang(), key, args...)
+}
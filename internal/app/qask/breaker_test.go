@@ -0,0 +1,63 @@
+package qask
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := newBreaker(3, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		b.failure()
+		if !b.allow() {
+			t.Fatalf("breaker is open after %d failures", i+1)
+		}
+	}
+
+	b.failure()
+	if b.allow() {
+		t.Error("breaker is closed after 3 failures")
+	}
+}
+
+func TestBreakerSuccessResets(t *testing.T) {
+	b := newBreaker(2, time.Hour)
+
+	b.failure()
+	b.success()
+	b.failure()
+	if !b.allow() {
+		t.Error("failures before a success are counted")
+	}
+}
+
+func TestBreakerTrial(t *testing.T) {
+	b := newBreaker(1, 20*time.Millisecond)
+
+	b.failure()
+	if b.allow() {
+		t.Fatal("breaker is closed during the cooldown")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("trial call is not allowed after the cooldown")
+	}
+	if b.allow() {
+		t.Error("a second call is allowed during the trial")
+	}
+
+	// a failed trial opens the breaker for another cooldown
+	b.failure()
+	if b.allow() {
+		t.Error("breaker is closed after a failed trial")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	b.allow()
+	b.success()
+	if !b.allow() || !b.allow() {
+		t.Error("breaker is open after a successful trial")
+	}
+}
@@ -0,0 +1,305 @@
+package qask
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/model"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	endpointUsers     = "/users"
+	endpointQuestions = "/questions"
+	//requestTimeout is the deadline of a call including all its retries
+	requestTimeout = 5 * time.Second
+
+	//maxRetries is the number of retries of an idempotent call
+	maxRetries = 3
+	//retryBackoff is the delay before the first retry, it doubles with every retry
+	retryBackoff = 200 * time.Millisecond
+
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+//ErrUnavailable is returned without calling qask while the circuit breaker is open
+var ErrUnavailable = errors.New("qask is temporarily unavailable")
+
+//IsUnavailable reports whether the error means qask can not be reached rather than it has rejected the call
+func IsUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
+
+//Client is a client of the qask API
+type Client struct {
+	url     string
+	client  *http.Client
+	breaker *breaker
+	logger  *logrus.Logger
+}
+
+//New creates a qask client
+func New(url string, logger *logrus.Logger) *Client {
+	return &Client{
+		url:     url,
+		client:  &http.Client{},
+		breaker: newBreaker(breakerThreshold, breakerCooldown),
+		logger:  logger,
+	}
+}
+
+//RegisterUser registers a telegram user in qask
+func (c *Client) RegisterUser(ctx context.Context, user *model.User) error {
+	type request struct {
+		FirstName string `json:"firstName"`
+		UserName  string `json:"userName"`
+		TgID      int64  `json:"tgId"`
+		From      string `json:"from"`
+	}
+
+	req := &request{
+		FirstName: user.FirstName,
+		UserName:  user.UserName,
+		TgID:      user.UserID(),
+		From:      "telegram",
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, endpointUsers, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp)
+	}
+
+	return nil
+}
+
+//AddQuestion publishes a question submitted by a telegram user and returns its ID in qask
+func (c *Client) AddQuestion(ctx context.Context, tgID int64, q *model.Question) (int, error) {
+	type request struct {
+		TgID     int64  `json:"tgId"`
+		From     string `json:"from"`
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+		Comment  string `json:"comment,omitempty"`
+		Source   string `json:"source,omitempty"`
+		Author   string `json:"author,omitempty"`
+	}
+
+	req := &request{
+		TgID:     tgID,
+		From:     "telegram",
+		Question: q.Question,
+		Answer:   q.Answer,
+		Comment:  q.Comment,
+		Source:   q.Source,
+		Author:   q.Author,
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, endpointQuestions, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return 0, responseError(resp)
+	}
+
+	created := &model.Question{}
+	if err := json.NewDecoder(resp.Body).Decode(created); err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+//GetQuestion returns a random question matching the filter for a telegram user or group chat
+func (c *Client) GetQuestion(ctx context.Context, tgID int64, filter model.QuestionFilter) (*model.Question, error) {
+	type request struct {
+		TgID       int64    `json:"tgId"`
+		From       string   `json:"from"`
+		Categories []string `json:"categories,omitempty"`
+		Difficulty int      `json:"difficulty,omitempty"`
+		Exclude    []int    `json:"exclude,omitempty"`
+		Search     string   `json:"search,omitempty"`
+	}
+
+	req := &request{
+		TgID:       tgID,
+		From:       "telegram",
+		Categories: filter.Categories,
+		Difficulty: filter.Difficulty,
+		Exclude:    filter.Exclude,
+		Search:     filter.Search,
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, endpointQuestions, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	q := &model.Question{}
+	if err := json.NewDecoder(resp.Body).Decode(q); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+//Ping checks that qask is reachable, any HTTP response is fine
+func (c *Client) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+//do calls qask through the circuit breaker. GET calls are idempotent and retried
+//with exponential backoff and jitter on network errors and server errors.
+//The deadline of requestTimeout or the earlier one of ctx covers all attempts and reading the response body
+func (c *Client) do(ctx context.Context, method string, endpoint string, body interface{}) (*http.Response, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	resp, err := c.retry(ctx, method, endpoint, b)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{
+		ReadCloser: resp.Body,
+		cancel:     cancel,
+	}
+
+	return resp, nil
+}
+
+func (c *Client) retry(ctx context.Context, method string, endpoint string, body []byte) (*http.Response, error) {
+	attempts := 1
+	if method == http.MethodGet {
+		attempts += maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff(attempt)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if !c.breaker.allow() {
+			return nil, ErrUnavailable
+		}
+
+		resp, err := c.request(ctx, method, endpoint, body)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			c.breaker.success()
+			return resp, nil
+		}
+		c.breaker.failure()
+
+		if attempt == attempts-1 || ctx.Err() != nil {
+			return resp, err
+		}
+
+		if err == nil {
+			resp.Body.Close()
+		}
+		c.logger.Warnf("Retrying request to qask: method=\"%s\" endpoint=\"%s\" attempt=\"%d\"", method, endpoint, attempt+1)
+	}
+}
+
+//backoff returns a random delay between a half and the whole of the exponentially growing limit of the retry
+func backoff(retry int) time.Duration {
+	limit := retryBackoff << uint(retry-1)
+	return limit/2 + time.Duration(rand.Int63n(int64(limit/2)))
+}
+
+//cancelBody cancels the request's context when the response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+//request makes a single call
+func (c *Client) request(ctx context.Context, method string, endpoint string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.url+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := c.client.Do(req.WithContext(ctx))
+	metrics.QaskRequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		c.logger.Errorf("Request to qask failed: method=\"%s\" endpoint=\"%s\" error=\"%s\"", method, endpoint, err)
+		metrics.QaskRequests.WithLabelValues(endpoint, "error").Inc()
+		metrics.QaskErrors.WithLabelValues(endpoint).Inc()
+		return nil, err
+	}
+
+	metrics.QaskRequests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		metrics.QaskErrors.WithLabelValues(endpoint).Inc()
+	}
+
+	return resp, nil
+}
+
+func responseError(resp *http.Response) error {
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if len(b) == 0 {
+		return errors.New(resp.Status)
+	}
+
+	return errors.New(string(b))
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+//Bucket is a token bucket: it holds up to capacity tokens and refills them at rate tokens per second
+type Bucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64
+	last     time.Time
+}
+
+//NewBucket creates a full bucket
+func NewBucket(capacity int, rate float64) *Bucket {
+	return &Bucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		rate:     rate,
+		last:     time.Now(),
+	}
+}
+
+func (b *Bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+//Allow takes a token if there is one
+func (b *Bucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+//Delay returns how long to wait until a token is available
+func (b *Bucket) Delay() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+//Wait blocks until a token is available and takes it
+func (b *Bucket) Wait() {
+	for !b.Allow() {
+		time.Sleep(b.Delay())
+	}
+}
+
+//Full reports whether the bucket is refilled completely, i.e. it has not been used for a while
+func (b *Bucket) Full() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	return b.tokens >= b.capacity
+}
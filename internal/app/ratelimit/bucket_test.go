@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketBurst(t *testing.T) {
+	b := NewBucket(3, 1)
+	if !b.Full() {
+		t.Error("a new bucket is not full")
+	}
+
+	for i := 0; i < 3; i++ {
+		if !b.Allow() {
+			t.Fatalf("token %d of the burst is not allowed", i+1)
+		}
+	}
+
+	if b.Allow() {
+		t.Error("a token over the burst is allowed")
+	}
+
+	if b.Full() {
+		t.Error("an empty bucket is full")
+	}
+}
+
+func TestBucketRefill(t *testing.T) {
+	b := NewBucket(1, 20)
+	if !b.Allow() {
+		t.Fatal("the first token is not allowed")
+	}
+
+	delay := b.Delay()
+	if delay <= 0 || delay > 50*time.Millisecond {
+		t.Fatalf("delay %s, want up to 50ms", delay)
+	}
+
+	time.Sleep(delay)
+	if !b.Allow() {
+		t.Error("a token is not allowed after the delay")
+	}
+}
+
+func TestBucketWait(t *testing.T) {
+	b := NewBucket(1, 20)
+	b.Wait()
+
+	start := time.Now()
+	b.Wait()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("waited %s for a token refilled in 50ms", elapsed)
+	}
+}
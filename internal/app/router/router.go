@@ -3,35 +3,49 @@ package router
 import (
 	"github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/metrics"
 	"qask_telegram/internal/app/model"
 	"strings"
+	"time"
 )
 
 type RouterHandler func(*model.User, *tgbotapi.Update)
 
+//Middleware wraps a handler, e.g. to check access rights
+type Middleware func(RouterHandler) RouterHandler
+
 type Route struct {
 	isPublic     bool
 	routeHandler RouterHandler
 }
 
 type Router struct {
-	r      map[string]*Route
-	logger *logrus.Logger
+	name    string
+	botName string
+	r       map[string]*Route
+	logger  *logrus.Logger
 }
 
-func NewRouter(logger *logrus.Logger) *Router {
+//NewRouter creates a router, botName is the username of the bot which commands in group chats are addressed to
+func NewRouter(name string, botName string, logger *logrus.Logger) *Router {
 	return &Router{
-		r:      make(map[string]*Route),
-		logger: logger,
+		name:    name,
+		botName: botName,
+		r:       make(map[string]*Route),
+		logger:  logger,
 	}
 }
 
-func (r *Router) NewRoute(path string, isPublic bool, handler RouterHandler) *Router {
+func (r *Router) NewRoute(path string, isPublic bool, handler RouterHandler, middlewares ...Middleware) *Router {
 	r.logger.Debugf("Creating new route '%s'", path)
 	if path == "" {
 		return nil
 	}
 
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
 	newRoute := &Route{
 		isPublic:     isPublic,
 		routeHandler: handler,
@@ -44,26 +58,61 @@ func (r *Router) NewRoute(path string, isPublic bool, handler RouterHandler) *Ro
 func (r *Router) GetHandler(path string) RouterHandler {
 	r.logger.Debugf("Looking for handler '%s'", path)
 
-	route, ok := r.r[path]
+	command := r.command(path)
+	route, ok := r.r[command]
 	if !ok {
 		return nil
 	}
 
-	return route.routeHandler
+	handler := route.routeHandler
+	return func(user *model.User, u *tgbotapi.Update) {
+		start := time.Now()
+		handler(user, u)
+		metrics.HandlerDuration.WithLabelValues(r.name, command).Observe(time.Since(start).Seconds())
+	}
 }
 
 func (r *Router) CommandIsRegistered(command string) bool {
-	tmpCommand := strings.TrimRight(command, " ")
-	_, ok := r.r[tmpCommand]
+	_, ok := r.r[r.command(command)]
 	return ok
 }
 
 func (r *Router) CommandIsPublic(command string) bool {
-	tmpCommand := strings.TrimRight(command, " ")
-	route, ok := r.r[tmpCommand]
+	route, ok := r.r[r.command(command)]
 	if !ok {
 		return false
 	}
 
 	return route.isPublic
 }
+
+//command returns the command of the text without the bot name of group chats ("/question@qask_bot").
+//A command addressed to another bot is empty, so it has no route
+func (r *Router) command(text string) string {
+	command, _ := ParseCommand(text)
+	command, botName := SplitBotName(command)
+	if botName != "" && !strings.EqualFold(botName, r.botName) {
+		return ""
+	}
+
+	return command
+}
+
+//ParseCommand splits a command text like "/setLanguage en" into the command and its arguments
+func ParseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	return fields[0], fields[1:]
+}
+
+//SplitBotName splits a command sent in a group chat ("/question@qask_bot") into the command and the bot name
+func SplitBotName(command string) (string, string) {
+	if i := strings.Index(command, "@"); i != -1 {
+		return command[:i], command[i+1:]
+	}
+
+	return command, ""
+}
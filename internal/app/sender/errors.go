@@ -0,0 +1,39 @@
+package sender
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+var errorCodes = map[string]string{
+	"Bad Request":       "400",
+	"Unauthorized":      "401",
+	"Forbidden":         "403",
+	"Not Found":         "404",
+	"Conflict":          "409",
+	"Too Many Requests": "429",
+}
+
+//IsBlocked reports whether the message was not delivered because the user has blocked the bot
+//or the bot can not write to the chat any more
+func IsBlocked(err error) bool {
+	return ErrorCode(err) == "403"
+}
+
+//ErrorCode returns the code of an error returned by telegram.
+//Telegram API errors are only available as descriptions like "Forbidden: bot was blocked by the user"
+func ErrorCode(err error) string {
+	tgErr, ok := err.(tgbotapi.Error)
+	if !ok {
+		return "network"
+	}
+
+	if i := strings.Index(tgErr.Message, ":"); i != -1 {
+		if code, ok := errorCodes[tgErr.Message[:i]]; ok {
+			return code
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,403 @@
+package sender
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"qask_telegram/internal/app/metrics"
+	"qask_telegram/internal/app/ratelimit"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/sirupsen/logrus"
+)
+
+//Telegram limits, see https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+const (
+	globalRate     = 30
+	privateRate    = 1
+	groupRate      = 20.0 / 60
+	chatBurst      = 3
+	maxRetries     = 3
+	highQueueSize  = 1000
+	lowQueueSize   = 10000
+	cleanupTimeout = time.Minute
+)
+
+//Priority of an outgoing message
+type Priority int
+
+const (
+	//PriorityHigh is used for interactive replies to users
+	PriorityHigh Priority = iota
+	//PriorityLow is used for pushes and broadcasts
+	PriorityLow
+)
+
+var (
+	//ErrQueueFull is returned when a message is dropped because the queue is full
+	ErrQueueFull = errors.New("send queue is full")
+)
+
+//Stats are counters of the sender
+type Stats struct {
+	Sent    uint64
+	Retried uint64
+	Failed  uint64
+	Dropped uint64
+}
+
+type result struct {
+	msg tgbotapi.Message
+	raw json.RawMessage
+	err error
+}
+
+type job struct {
+	c        tgbotapi.Chattable
+	method   string
+	params   url.Values
+	chatID   int64
+	priority Priority
+	attempts int
+	result   chan result
+}
+
+//chat is a per chat limit and the messages to the chat in the order they were queued,
+//the queues only tell the worker which chat has a message to send
+type chat struct {
+	bucket  *ratelimit.Bucket
+	jobs    []*job
+	waiting bool
+}
+
+//Sender sends messages to telegram respecting global and per chat limits,
+//messages to one chat are sent in the order they were queued
+type Sender struct {
+	bot       *tgbotapi.BotAPI
+	logger    *logrus.Logger
+	global    *ratelimit.Bucket
+	mu        sync.Mutex
+	chats     map[int64]*chat
+	high      chan *job
+	low       chan *job
+	resume    chan int64
+	stats     Stats
+	onBlocked func(chatID int64)
+}
+
+//New creates a sender and starts its workers
+func New(bot *tgbotapi.BotAPI, logger *logrus.Logger) *Sender {
+	s := &Sender{
+		bot:    bot,
+		logger: logger,
+		global: ratelimit.NewBucket(globalRate, globalRate),
+		chats:  make(map[int64]*chat),
+		high:   make(chan *job, highQueueSize),
+		low:    make(chan *job, lowQueueSize),
+		resume: make(chan int64, highQueueSize),
+	}
+
+	go s.run()
+	go s.cleanup()
+
+	return s
+}
+
+//Send sends an interactive message and waits for the result
+func (s *Sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return s.SendPriority(c, PriorityHigh)
+}
+
+//SendPriority queues a message with the priority and waits for the result
+func (s *Sender) SendPriority(c tgbotapi.Chattable, priority Priority) (tgbotapi.Message, error) {
+	j := s.newJob(c, priority)
+	j.result = make(chan result, 1)
+
+	if !s.enqueue(j) {
+		return tgbotapi.Message{}, ErrQueueFull
+	}
+
+	res := <-j.result
+	return res.msg, res.err
+}
+
+//Request queues a raw request to a bot API method which tgbotapi does not support
+//and waits for the result. The params must contain the chat_id the request is addressed to
+func (s *Sender) Request(method string, params url.Values, priority Priority) (json.RawMessage, error) {
+	chatID, _ := strconv.ParseInt(params.Get("chat_id"), 10, 64)
+	j := &job{
+		method:   method,
+		params:   params,
+		chatID:   chatID,
+		priority: priority,
+		result:   make(chan result, 1),
+	}
+
+	if !s.enqueue(j) {
+		return nil, ErrQueueFull
+	}
+
+	res := <-j.result
+	return res.raw, res.err
+}
+
+//Push queues a message with low priority without waiting for it to be sent
+func (s *Sender) Push(c tgbotapi.Chattable) error {
+	if !s.enqueue(s.newJob(c, PriorityLow)) {
+		return ErrQueueFull
+	}
+
+	return nil
+}
+
+//OnBlocked sets a function called when telegram refuses to deliver messages to a chat,
+//e.g. when a user has blocked the bot
+func (s *Sender) OnBlocked(onBlocked func(chatID int64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.onBlocked = onBlocked
+}
+
+//Stats returns a snapshot of the sender counters
+func (s *Sender) Stats() Stats {
+	return Stats{
+		Sent:    atomic.LoadUint64(&s.stats.Sent),
+		Retried: atomic.LoadUint64(&s.stats.Retried),
+		Failed:  atomic.LoadUint64(&s.stats.Failed),
+		Dropped: atomic.LoadUint64(&s.stats.Dropped),
+	}
+}
+
+func (s *Sender) newJob(c tgbotapi.Chattable, priority Priority) *job {
+	return &job{
+		c:        c,
+		chatID:   chatID(c),
+		priority: priority,
+	}
+}
+
+func (s *Sender) enqueue(j *job) bool {
+	queue := s.high
+	if j.priority == PriorityLow {
+		queue = s.low
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var c *chat
+	if j.chatID != 0 {
+		c = s.chat(j.chatID)
+		c.jobs = append(c.jobs, j)
+	}
+
+	select {
+	case queue <- j:
+		return true
+	default:
+		if c != nil {
+			c.jobs = c.jobs[:len(c.jobs)-1]
+		}
+		atomic.AddUint64(&s.stats.Dropped, 1)
+		s.logger.Warnf("Send queue is full, message to chat \"%d\" dropped", j.chatID)
+		return false
+	}
+}
+
+func (s *Sender) requeueAfter(j *job, delay time.Duration) {
+	time.AfterFunc(delay, func() {
+		if !s.enqueue(j) {
+			s.done(j, tgbotapi.Message{}, nil, ErrQueueFull)
+		}
+	})
+}
+
+//resumeAfter sends the messages of the chat again after the delay
+func (s *Sender) resumeAfter(c *chat, chatID int64, delay time.Duration) {
+	s.mu.Lock()
+	c.waiting = true
+	s.mu.Unlock()
+
+	time.AfterFunc(delay, func() {
+		s.resume <- chatID
+	})
+}
+
+func (s *Sender) run() {
+	for {
+		select {
+		case chatID := <-s.resume:
+			s.resumed(chatID)
+		case j := <-s.high:
+			s.process(j)
+		default:
+			select {
+			case chatID := <-s.resume:
+				s.resumed(chatID)
+			case j := <-s.high:
+				s.process(j)
+			case j := <-s.low:
+				s.process(j)
+			}
+		}
+	}
+}
+
+func (s *Sender) process(j *job) {
+	if j.chatID == 0 {
+		if delay := s.send(j); delay > 0 {
+			s.requeueAfter(j, delay)
+		}
+		return
+	}
+
+	s.flush(j.chatID)
+}
+
+func (s *Sender) resumed(chatID int64) {
+	s.mu.Lock()
+	if c, ok := s.chats[chatID]; ok {
+		c.waiting = false
+	}
+	s.mu.Unlock()
+
+	s.flush(chatID)
+}
+
+//flush sends the messages of the chat in order until the chat limit is exceeded
+//or telegram asks to retry later, then the rest is sent by resumeAfter
+func (s *Sender) flush(chatID int64) {
+	for {
+		s.mu.Lock()
+		c, ok := s.chats[chatID]
+		if !ok || c.waiting || len(c.jobs) == 0 {
+			s.mu.Unlock()
+			return
+		}
+
+		j := c.jobs[0]
+		if !c.bucket.Allow() {
+			delay := c.bucket.Delay()
+			s.mu.Unlock()
+			s.resumeAfter(c, chatID, delay)
+			return
+		}
+		s.mu.Unlock()
+
+		if delay := s.send(j); delay > 0 {
+			s.resumeAfter(c, chatID, delay)
+			return
+		}
+
+		s.mu.Lock()
+		c.jobs[0] = nil
+		c.jobs = c.jobs[1:]
+		s.mu.Unlock()
+	}
+}
+
+//send makes the request, it returns a delay if telegram asks to retry the request later
+func (s *Sender) send(j *job) time.Duration {
+	s.global.Wait()
+
+	var (
+		msg tgbotapi.Message
+		raw json.RawMessage
+		err error
+	)
+	if j.method != "" {
+		var resp tgbotapi.APIResponse
+		resp, err = s.bot.MakeRequest(j.method, j.params)
+		raw = resp.Result
+	} else {
+		msg, err = s.bot.Send(j.c)
+	}
+
+	if err != nil {
+		if tgErr, ok := err.(tgbotapi.Error); ok && tgErr.RetryAfter > 0 && j.attempts < maxRetries {
+			j.attempts++
+			atomic.AddUint64(&s.stats.Retried, 1)
+			s.logger.Warnf("Too many requests to chat \"%d\", retry after %d s", j.chatID, tgErr.RetryAfter)
+			return time.Duration(tgErr.RetryAfter) * time.Second
+		}
+
+		code := ErrorCode(err)
+		atomic.AddUint64(&s.stats.Failed, 1)
+		metrics.SendErrors.WithLabelValues(code).Inc()
+		s.logger.Errorf("Can not send message to chat \"%d\": %s", j.chatID, err)
+
+		if IsBlocked(err) && j.chatID != 0 {
+			s.blocked(j.chatID)
+		}
+	} else {
+		atomic.AddUint64(&s.stats.Sent, 1)
+	}
+
+	s.done(j, msg, raw, err)
+	return 0
+}
+
+func (s *Sender) blocked(chatID int64) {
+	s.mu.Lock()
+	onBlocked := s.onBlocked
+	s.mu.Unlock()
+
+	if onBlocked != nil {
+		onBlocked(chatID)
+	}
+}
+
+func (s *Sender) done(j *job, msg tgbotapi.Message, raw json.RawMessage, err error) {
+	if j.result != nil {
+		j.result <- result{msg: msg, raw: raw, err: err}
+	}
+}
+
+//chat returns the state of the chat, s.mu must be held
+func (s *Sender) chat(chatID int64) *chat {
+	c, ok := s.chats[chatID]
+	if !ok {
+		rate := float64(privateRate)
+		if chatID < 0 {
+			rate = groupRate
+		}
+
+		c = &chat{bucket: ratelimit.NewBucket(chatBurst, rate)}
+		s.chats[chatID] = c
+	}
+
+	return c
+}
+
+func (s *Sender) cleanup() {
+	for range time.Tick(cleanupTimeout) {
+		s.mu.Lock()
+		for chatID, c := range s.chats {
+			if len(c.jobs) == 0 && c.bucket.Full() {
+				delete(s.chats, chatID)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+//chatID extracts the chat a message is addressed to, 0 if there is none
+func chatID(c tgbotapi.Chattable) int64 {
+	v := reflect.Indirect(reflect.ValueOf(c))
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+
+	f := v.FieldByName("ChatID")
+	if !f.IsValid() || f.Kind() != reflect.Int64 {
+		return 0
+	}
+
+	return f.Int()
+}
@@ -0,0 +1,112 @@
+package sender
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/sirupsen/logrus"
+)
+
+//fakeTelegram answers bot API requests and records sent messages as "chat:text"
+type fakeTelegram struct {
+	mu      sync.Mutex
+	sent    []string
+	tooMany map[string]bool
+}
+
+func (f *fakeTelegram) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"ok":true,"result":{"id":1,"is_bot":true,"username":"test_bot"}}`
+	if strings.HasSuffix(r.URL.Path, "/sendMessage") {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+
+		chat, text := r.PostForm.Get("chat_id"), r.PostForm.Get("text")
+
+		f.mu.Lock()
+		if f.tooMany[text] {
+			delete(f.tooMany, text)
+			body = `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`
+		} else {
+			f.sent = append(f.sent, chat+":"+text)
+			body = `{"ok":true,"result":{"message_id":1,"chat":{"id":` + chat + `},"text":"` + text + `"}}`
+		}
+		f.mu.Unlock()
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    r,
+	}, nil
+}
+
+func newTestSender(t *testing.T, telegram *fakeTelegram) *Sender {
+	bot, err := tgbotapi.NewBotAPIWithClient("token", &http.Client{Transport: telegram})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+
+	return New(bot, logger)
+}
+
+func TestSendKeepsChatOrder(t *testing.T) {
+	telegram := &fakeTelegram{tooMany: map[string]bool{"1": true}}
+	s := newTestSender(t, telegram)
+
+	for _, text := range []string{"1", "2"} {
+		if err := s.Push(tgbotapi.NewMessage(1, text)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Push(tgbotapi.NewMessage(2, "x")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Send(tgbotapi.NewMessage(1, "3")); err != nil {
+		t.Fatal(err)
+	}
+
+	telegram.mu.Lock()
+	defer telegram.mu.Unlock()
+
+	want := []string{"2:x", "1:1", "1:2", "1:3"}
+	if strings.Join(telegram.sent, " ") != strings.Join(want, " ") {
+		t.Errorf("sent %v, want %v", telegram.sent, want)
+	}
+
+	if stats := s.Stats(); stats.Retried != 1 || stats.Sent != 4 {
+		t.Errorf("stats %+v, want 1 retried and 4 sent", stats)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want string
+	}{
+		{tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"}, "403"},
+		{tgbotapi.Error{Message: "Too Many Requests: retry after 5"}, "429"},
+		{tgbotapi.Error{Message: "Bad Request: chat not found"}, "400"},
+		{tgbotapi.Error{Message: "something went wrong"}, "unknown"},
+		{errors.New("connection refused"), "network"},
+	}
+
+	for _, tc := range testCases {
+		if got := ErrorCode(tc.err); got != tc.want {
+			t.Errorf("ErrorCode(%q) = %q, want %q", tc.err, got, tc.want)
+		}
+	}
+
+	if !IsBlocked(tgbotapi.Error{Message: "Forbidden: bot was kicked from the group chat"}) {
+		t.Error("a kicked bot is not reported as blocked")
+	}
+}
@@ -0,0 +1,165 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"qask_telegram/internal/app/model"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//Local gives random questions of packs loaded from files
+type Local struct {
+	mu        sync.Mutex
+	questions []*model.Question
+	rand      *rand.Rand
+	logger    *logrus.Logger
+}
+
+//NewLocal creates a local source of the questions
+func NewLocal(questions []*model.Question, logger *logrus.Logger) *Local {
+	// Local questions get negative IDs so they do not collide with the IDs of qask questions
+	for i, q := range questions {
+		q.ID = -(i + 1)
+	}
+
+	return &Local{
+		questions: questions,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		logger:    logger,
+	}
+}
+
+//LoadDir loads all packs of the directory: *.json, *.csv and *.txt in the db.chgk.info format
+func LoadDir(dir string, logger *logrus.Logger) (*Local, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]*model.Question, 0)
+	for _, file := range files {
+		if file.IsDir() || !isPack(file.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		pack, err := LoadPack(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+
+		// Media files are referred relative to the directory of packs
+		for _, q := range pack {
+			if q.Media != nil && q.Media.File != "" && !filepath.IsAbs(q.Media.File) {
+				q.Media.File = filepath.Join(dir, q.Media.File)
+			}
+		}
+
+		logger.Infof("Question pack \"%s\" loaded: %d questions", path, len(pack))
+		questions = append(questions, pack...)
+	}
+
+	return NewLocal(questions, logger), nil
+}
+
+func isPack(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".csv", ".txt":
+		return true
+	}
+
+	return false
+}
+
+//LoadPack reads questions of a pack, the format is chosen by the file extension
+func LoadPack(path string) ([]*model.Question, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSON(f)
+	case ".csv":
+		return ParseCSV(f)
+	case ".txt":
+		return ParseChgk(f)
+	}
+
+	return nil, fmt.Errorf("unknown pack format \"%s\"", filepath.Ext(path))
+}
+
+//Len returns the number of questions
+func (l *Local) Len() int {
+	return len(l.questions)
+}
+
+//GetQuestion returns a random question matching the filter. When all matching questions are excluded,
+//one of them is returned anyway, repeats are better than no question
+func (l *Local) GetQuestion(ctx context.Context, tgID int64, filter model.QuestionFilter) (*model.Question, error) {
+	exclude := make(map[int]struct{}, len(filter.Exclude))
+	for _, id := range filter.Exclude {
+		exclude[id] = struct{}{}
+	}
+
+	matching := make([]*model.Question, 0)
+	fresh := make([]*model.Question, 0)
+	for _, q := range l.questions {
+		if !match(q, filter) {
+			continue
+		}
+
+		matching = append(matching, q)
+		if _, ok := exclude[q.ID]; !ok {
+			fresh = append(fresh, q)
+		}
+	}
+
+	if len(fresh) != 0 {
+		matching = fresh
+	}
+
+	if len(matching) == 0 {
+		return nil, ErrNoQuestions
+	}
+
+	l.mu.Lock()
+	i := l.rand.Intn(len(matching))
+	l.mu.Unlock()
+
+	q := *matching[i]
+	return &q, nil
+}
+
+//match reports whether the question fits the preferences, questions without a category or difficulty fit any
+func match(q *model.Question, filter model.QuestionFilter) bool {
+	if filter.Search != "" && !strings.Contains(strings.ToLower(q.Question), strings.ToLower(filter.Search)) {
+		return false
+	}
+
+	if filter.Difficulty != model.DifficultyAny && q.Difficulty != model.DifficultyAny && q.Difficulty != filter.Difficulty {
+		return false
+	}
+
+	if len(filter.Categories) == 0 || q.Category == "" {
+		return true
+	}
+
+	for _, category := range filter.Categories {
+		if strings.EqualFold(category, q.Category) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,221 @@
+package source
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"qask_telegram/internal/app/model"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//ParseJSON reads an array of questions with the fields of model.Question
+func ParseJSON(r io.Reader) ([]*model.Question, error) {
+	questions := make([]*model.Question, 0)
+	if err := json.NewDecoder(r).Decode(&questions); err != nil {
+		return nil, err
+	}
+
+	return validate(questions)
+}
+
+//ParseCSV reads questions from a CSV file with a header. The columns question and answer are required,
+//comment, category, difficulty, source, author, format, media (URL or file), media_type, tags and options
+//of a multiple-choice question separated by ";" are optional
+func ParseCSV(r io.Reader) ([]*model.Question, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["question"]; !ok {
+		return nil, errors.New("there is no \"question\" column")
+	}
+	if _, ok := columns["answer"]; !ok {
+		return nil, errors.New("there is no \"answer\" column")
+	}
+
+	questions := make([]*model.Question, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		q := &model.Question{
+			Question: field("question"),
+			Answer:   field("answer"),
+			Comment:  field("comment"),
+			Category: field("category"),
+			Source:   field("source"),
+			Author:   field("author"),
+			Format:   field("format"),
+		}
+
+		if media := field("media"); media != "" {
+			q.Media = &model.Media{
+				Type: field("media_type"),
+			}
+			if strings.Contains(media, "://") {
+				q.Media.URL = media
+			} else {
+				q.Media.File = media
+			}
+		}
+
+		if difficulty := field("difficulty"); difficulty != "" {
+			q.Difficulty, err = strconv.Atoi(difficulty)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", len(questions)+2, err)
+			}
+		}
+
+		for _, tag := range strings.Split(field("tags"), ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				q.Tags = append(q.Tags, tag)
+			}
+		}
+
+		for _, option := range strings.Split(field("options"), ";") {
+			if option = strings.TrimSpace(option); option != "" {
+				q.Options = append(q.Options, option)
+			}
+		}
+
+		questions = append(questions, q)
+	}
+
+	return validate(questions)
+}
+
+//chgkPicture is a reference to a picture of db.chgk.info in a question text like "(pic: 20090101.jpg)"
+var chgkPicture = regexp.MustCompile(`\(pic: ?([^)\s]+)\)`)
+
+//chgkImages is the address of pictures of db.chgk.info
+const chgkImages = "https://db.chgk.info/images/db/"
+
+//chgkField matches the first line of a field of the db.chgk.info format like "Вопрос 3:"
+var chgkField = regexp.MustCompile(`^(Чемпионат|Дата|Редактор|Инфо|Тур|Вид|Тип|Вопрос|Ответ|Зач[её]т|Незач[её]т|Комментарий|Источник|Источники|Автор|Авторы)(?: \d+)?:\s*(.*)$`)
+
+//ParseChgk reads questions in the plain-text format of db.chgk.info. A field starts with a line like
+//"Вопрос 1:", "Ответ:", "Комментарий:", "Источник:" or "Автор:" and lasts until the next field,
+//other fields like "Чемпионат:" or "Зачет:" are skipped
+func ParseChgk(r io.Reader) ([]*model.Question, error) {
+	questions := make([]*model.Question, 0)
+
+	var q *model.Question
+	var field *string
+	var text []string
+
+	flush := func() {
+		if field != nil {
+			*field = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		field, text = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		m := chgkField.FindStringSubmatch(line)
+		if m == nil {
+			if field != nil {
+				text = append(text, line)
+			}
+			continue
+		}
+
+		flush()
+
+		var skip string
+		field = &skip
+		switch strings.ToLower(m[1]) {
+		case "вопрос":
+			q = &model.Question{}
+			questions = append(questions, q)
+			field = &q.Question
+		case "ответ":
+			if q != nil {
+				field = &q.Answer
+			}
+		case "комментарий":
+			if q != nil {
+				field = &q.Comment
+			}
+		case "источник", "источники":
+			if q != nil {
+				field = &q.Source
+			}
+		case "автор", "авторы":
+			if q != nil {
+				field = &q.Author
+			}
+		}
+
+		if m[2] != "" {
+			text = append(text, m[2])
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, q := range questions {
+		if m := chgkPicture.FindStringSubmatch(q.Question); m != nil {
+			q.Media = &model.Media{
+				Type: model.MediaPhoto,
+				URL:  chgkImages + m[1],
+			}
+			q.Question = strings.TrimSpace(chgkPicture.ReplaceAllString(q.Question, ""))
+		}
+	}
+
+	return validate(questions)
+}
+
+func validate(questions []*model.Question) ([]*model.Question, error) {
+	for i, q := range questions {
+		if len(q.Options) > 0 {
+			if err := q.SetOptions(q.Options); err != nil {
+				return nil, fmt.Errorf("question %d: %s", i+1, err)
+			}
+			if !q.IsQuiz() {
+				return nil, fmt.Errorf("question %d: there must be %d to %d options of at most %d characters",
+					i+1, model.MinOptions, model.MaxOptions, model.MaxOptionLength)
+			}
+		}
+
+		if q.Question == "" || q.Answer == "" {
+			return nil, fmt.Errorf("question %d has no text or answer", i+1)
+		}
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,193 @@
+package source
+
+import (
+	"fmt"
+	"io"
+	"qask_telegram/internal/app/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type parseTestCase struct {
+	name    string
+	input   string
+	want    []*model.Question
+	wantErr bool
+}
+
+func testParse(t *testing.T, parse func(io.Reader) ([]*model.Question, error), testCases []parseTestCase) {
+	for _, tc := range testCases {
+		got, err := parse(strings.NewReader(tc.input))
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: want an error, got %d questions", tc.name, len(got))
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.name, questionsString(got), questionsString(tc.want))
+		}
+	}
+}
+
+func questionsString(questions []*model.Question) string {
+	s := make([]string, 0, len(questions))
+	for _, q := range questions {
+		s = append(s, fmt.Sprintf("%+v", *q))
+	}
+
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+func TestParseJSON(t *testing.T) {
+	testParse(t, ParseJSON, []parseTestCase{
+		{
+			name:  "questions",
+			input: `[{"question": "Q1", "answer": "A1", "comment": "C1", "difficulty": 2, "tags": ["t"]}, {"question": "Q2", "answer": "A2"}]`,
+			want: []*model.Question{
+				{Question: "Q1", Answer: "A1", Comment: "C1", Difficulty: 2, Tags: []string{"t"}},
+				{Question: "Q2", Answer: "A2"},
+			},
+		},
+		{
+			name:  "multiple choice",
+			input: `[{"question": "Q", "answer": "b", "options": ["a", "b", "c"]}]`,
+			want: []*model.Question{
+				{Question: "Q", Answer: "b", Options: []string{"a", "b", "c"}, CorrectOption: 1},
+			},
+		},
+		{
+			name:  "empty array",
+			input: `[]`,
+			want:  []*model.Question{},
+		},
+		{
+			name:    "empty file",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			input:   `[{"question": "Q"`,
+			wantErr: true,
+		},
+		{
+			name:    "no answer",
+			input:   `[{"question": "Q"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "answer is not an option",
+			input:   `[{"question": "Q", "answer": "d", "options": ["a", "b"]}]`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestParseCSV(t *testing.T) {
+	testParse(t, ParseCSV, []parseTestCase{
+		{
+			name: "questions",
+			input: "Question,Answer,Comment,Difficulty,Tags\n" +
+				"Q1,A1,C1,3,a; b\n" +
+				"\"Q2, with a comma\",A2\n",
+			want: []*model.Question{
+				{Question: "Q1", Answer: "A1", Comment: "C1", Difficulty: 3, Tags: []string{"a", "b"}},
+				{Question: "Q2, with a comma", Answer: "A2"},
+			},
+		},
+		{
+			name: "media and options",
+			input: "question,answer,media,media_type,options\n" +
+				"Q1,A1,https://example.com/1.jpg,photo,\n" +
+				"Q2,A2,2.mp3,audio,\n" +
+				"Q3,b,,,a;b;c\n",
+			want: []*model.Question{
+				{Question: "Q1", Answer: "A1", Media: &model.Media{Type: "photo", URL: "https://example.com/1.jpg"}},
+				{Question: "Q2", Answer: "A2", Media: &model.Media{Type: "audio", File: "2.mp3"}},
+				{Question: "Q3", Answer: "b", Options: []string{"a", "b", "c"}, CorrectOption: 1},
+			},
+		},
+		{
+			name:  "header only",
+			input: "question,answer\n",
+			want:  []*model.Question{},
+		},
+		{
+			name:    "empty file",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "no answer column",
+			input:   "question,comment\nQ,C\n",
+			wantErr: true,
+		},
+		{
+			name:    "malformed row",
+			input:   "question,answer\n\"Q1,A1\nQ2,A2\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad difficulty",
+			input:   "question,answer,difficulty\nQ,A,hard\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty answer",
+			input:   "question,answer\nQ,\n",
+			wantErr: true,
+		},
+	})
+}
+
+func TestParseChgk(t *testing.T) {
+	testParse(t, ParseChgk, []parseTestCase{
+		{
+			name: "questions",
+			input: "Чемпионат:\nКубок\n\nТур:\n1\n\n" +
+				"Вопрос 1:\nПервая строка\nвторая строка\n\n" +
+				"Ответ:\nОтвет 1\n\nЗачет: ответ один\n\n" +
+				"Комментарий:\nКомментарий 1\n\nИсточник:\nhttps://example.com\n\nАвтор:\nИван Иванов\n\n" +
+				"Вопрос 2: В одну строку\r\nОтвет: Ответ 2\r\n",
+			want: []*model.Question{
+				{
+					Question: "Первая строка\nвторая строка",
+					Answer:   "Ответ 1",
+					Comment:  "Комментарий 1",
+					Source:   "https://example.com",
+					Author:   "Иван Иванов",
+				},
+				{Question: "В одну строку", Answer: "Ответ 2"},
+			},
+		},
+		{
+			name:  "picture",
+			input: "Вопрос 1:\n(pic: 20090101.jpg)\nЧто на картинке?\nОтвет:\nКот\n",
+			want: []*model.Question{
+				{
+					Question: "Что на картинке?",
+					Answer:   "Кот",
+					Media:    &model.Media{Type: model.MediaPhoto, URL: chgkImages + "20090101.jpg"},
+				},
+			},
+		},
+		{
+			name:  "empty file",
+			input: "",
+			want:  []*model.Question{},
+		},
+		{
+			name:    "missing answer",
+			input:   "Вопрос 1:\nВопрос без ответа\nКомментарий:\nКомментарий\n\nВопрос 2:\nВопрос\nОтвет:\nОтвет\n",
+			wantErr: true,
+		},
+	})
+}
@@ -0,0 +1,186 @@
+package source
+
+import (
+	"context"
+	"qask_telegram/internal/app/model"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+//bufferTimeout is how long the questions of a chat are kept after its last question
+const bufferTimeout = 30 * time.Minute
+
+//buffer is questions fetched ahead for a chat with the filter they were fetched with
+type buffer struct {
+	key        string
+	questions  []*model.Question
+	generation int
+	refilling  bool
+	used       time.Time
+}
+
+//Prefetcher keeps the next few questions of every chat ready and refills them in background,
+//so a question is usually given without waiting for the source
+type Prefetcher struct {
+	mu      sync.Mutex
+	source  QuestionSource
+	size    int
+	buffers map[int64]*buffer
+	logger  *logrus.Logger
+}
+
+//NewPrefetcher creates a prefetcher keeping size questions per chat, size 0 disables prefetching
+func NewPrefetcher(source QuestionSource, size int, logger *logrus.Logger) *Prefetcher {
+	p := &Prefetcher{
+		source:  source,
+		size:    size,
+		buffers: make(map[int64]*buffer),
+		logger:  logger,
+	}
+
+	go p.cleanup()
+
+	return p
+}
+
+//filterKey identifies preferences of a filter, the exclusions do not change which questions fit
+func filterKey(filter model.QuestionFilter) string {
+	return strconv.Itoa(filter.Difficulty) + "|" + strings.Join(filter.Categories, "|")
+}
+
+//GetQuestion returns a prefetched question if there is one for the filter, otherwise asks the source.
+//The buffer is refilled in background either way. Searches are not prefetched
+func (p *Prefetcher) GetQuestion(ctx context.Context, tgID int64, filter model.QuestionFilter) (*model.Question, error) {
+	if p.size == 0 || filter.Search != "" {
+		return p.source.GetQuestion(ctx, tgID, filter)
+	}
+
+	p.mu.Lock()
+	b, ok := p.buffers[tgID]
+	if !ok || b.key != filterKey(filter) {
+		b = p.reset(tgID, filter)
+	}
+	b.used = time.Now()
+
+	var q *model.Question
+	if len(b.questions) != 0 {
+		q, b.questions = b.questions[0], b.questions[1:]
+	}
+	p.refill(tgID, b, filter)
+	p.mu.Unlock()
+
+	if q != nil {
+		return q, nil
+	}
+
+	return p.source.GetQuestion(ctx, tgID, filter)
+}
+
+//Invalidate drops the questions prefetched for the chat, e.g. when its preferences change
+func (p *Prefetcher) Invalidate(tgID int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if b, ok := p.buffers[tgID]; ok {
+		b.key = ""
+		b.questions = nil
+		b.generation++
+	}
+}
+
+//reset replaces the chat's buffer with an empty one for the filter, p.mu must be held
+func (p *Prefetcher) reset(tgID int64, filter model.QuestionFilter) *buffer {
+	// A refill of the old buffer stops when it sees the generation changed
+	if old, ok := p.buffers[tgID]; ok {
+		old.generation++
+	}
+
+	b := &buffer{
+		key: filterKey(filter),
+	}
+	p.buffers[tgID] = b
+
+	return b
+}
+
+//cleanup drops the buffers of chats which have not asked for a question for a while
+func (p *Prefetcher) cleanup() {
+	for range time.Tick(bufferTimeout) {
+		p.mu.Lock()
+		for tgID, b := range p.buffers {
+			if time.Since(b.used) > bufferTimeout {
+				b.generation++
+				delete(p.buffers, tgID)
+			}
+		}
+		p.mu.Unlock()
+	}
+}
+
+//refill starts fetching questions until the buffer is full unless it is being refilled already, p.mu must be held
+func (p *Prefetcher) refill(tgID int64, b *buffer, filter model.QuestionFilter) {
+	if b.refilling || len(b.questions) >= p.size {
+		return
+	}
+	b.refilling = true
+	generation := b.generation
+
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			b.refilling = false
+			p.mu.Unlock()
+		}()
+
+		for {
+			p.mu.Lock()
+			if b.generation != generation || len(b.questions) >= p.size {
+				p.mu.Unlock()
+				return
+			}
+
+			// Questions in the buffer must not be fetched again
+			f := filter
+			f.Exclude = append([]int(nil), filter.Exclude...)
+			for _, q := range b.questions {
+				if q.ID != 0 {
+					f.Exclude = append(f.Exclude, q.ID)
+				}
+			}
+			p.mu.Unlock()
+
+			// Nobody waits for a refill, so it is limited only by the deadline of the source
+			q, err := p.source.GetQuestion(context.Background(), tgID, f)
+			if err != nil {
+				p.logger.Debugf("Can not prefetch a question for \"%d\": %s", tgID, err)
+				return
+			}
+
+			p.mu.Lock()
+			if b.generation != generation || contains(b.questions, q) {
+				p.mu.Unlock()
+				return
+			}
+			b.questions = append(b.questions, q)
+			p.mu.Unlock()
+		}
+	}()
+}
+
+func contains(questions []*model.Question, q *model.Question) bool {
+	if q.ID == 0 {
+		return false
+	}
+
+	for _, buffered := range questions {
+		if buffered.ID == q.ID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"qask_telegram/internal/app/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+//ErrNoQuestions is returned when a source has no question matching the filter
+var ErrNoQuestions = errors.New("there are no matching questions")
+
+//QuestionSource gives questions for a telegram user or group chat, the qask client is a source too
+type QuestionSource interface {
+	GetQuestion(ctx context.Context, tgID int64, filter model.QuestionFilter) (*model.Question, error)
+}
+
+//Chain asks its sources in order until one of them returns a question
+type Chain struct {
+	sources []QuestionSource
+	logger  *logrus.Logger
+}
+
+//NewChain creates a fallback chain of sources
+func NewChain(logger *logrus.Logger, sources ...QuestionSource) *Chain {
+	return &Chain{
+		sources: sources,
+		logger:  logger,
+	}
+}
+
+//GetQuestion returns a question of the first source that has one, or the error of the last source
+func (c *Chain) GetQuestion(ctx context.Context, tgID int64, filter model.QuestionFilter) (*model.Question, error) {
+	err := ErrNoQuestions
+	for i, s := range c.sources {
+		var q *model.Question
+		q, err = s.GetQuestion(ctx, tgID, filter)
+		if err == nil {
+			return q, nil
+		}
+
+		if i < len(c.sources)-1 {
+			c.logger.Warnf("Question source %d failed, falling back to the next one: %s", i, err)
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sync"
+)
+
+type GroupRepository struct {
+	mu     sync.RWMutex
+	groups map[int64]*model.Group
+	logger *logrus.Logger
+}
+
+func (g *GroupRepository) CreateGroup(chatID int64) *model.Group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if group, ok := g.groups[chatID]; ok {
+		return group
+	}
+
+	newGroup := &model.Group{
+		ChatID: chatID,
+		Scores: make(map[int]*model.GroupScore),
+	}
+
+	g.groups[chatID] = newGroup
+
+	g.logger.Infof("New group '%d'", chatID)
+	return newGroup
+}
+
+func (g *GroupRepository) FindGroup(chatID int64) *model.Group {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.groups[chatID]
+}
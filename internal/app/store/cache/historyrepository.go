@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sync"
+	"time"
+)
+
+//historyLimit is the number of the latest questions kept in a user's history
+const historyLimit = 100
+
+type HistoryRepository struct {
+	mu        sync.RWMutex
+	history   map[int][]*model.HistoryEntry
+	favorites map[int][]*model.Question
+	logger    *logrus.Logger
+}
+
+func (r *HistoryRepository) AddQuestion(userID int, question *model.Question) *model.HistoryEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &model.HistoryEntry{
+		Question: question,
+		AskedAt:  time.Now(),
+	}
+
+	history := append(r.history[userID], entry)
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+	r.history[userID] = history
+
+	return entry
+}
+
+//Recent returns at most limit entries of the user's history, the latest first
+func (r *HistoryRepository) Recent(userID int, limit int) []*model.HistoryEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := r.history[userID]
+	entries := make([]*model.HistoryEntry, 0, limit)
+	for i := len(history) - 1; i >= 0 && len(entries) < limit; i-- {
+		entries = append(entries, history[i])
+	}
+
+	return entries
+}
+
+//AddFavorite saves the question to the user's favorites, it reports whether the question was not saved before
+func (r *HistoryRepository) AddFavorite(userID int, question *model.Question) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, q := range r.favorites[userID] {
+		if q == question || (q.ID != 0 && q.ID == question.ID) {
+			return false
+		}
+	}
+
+	r.favorites[userID] = append(r.favorites[userID], question)
+
+	r.logger.Infof("User '%d' added a question to favorites", userID)
+	return true
+}
+
+//Favorites returns the user's favorite questions, the latest first
+func (r *HistoryRepository) Favorites(userID int) []*model.Question {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	favorites := r.favorites[userID]
+	questions := make([]*model.Question, 0, len(favorites))
+	for i := len(favorites) - 1; i >= 0; i-- {
+		questions = append(questions, favorites[i])
+	}
+
+	return questions
+}
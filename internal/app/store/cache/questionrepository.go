@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sync"
+)
+
+//maxSharedQuestions is how many shared questions are kept, the oldest ones are dropped first
+const maxSharedQuestions = 10000
+
+//QuestionRepository keeps questions shared outside of the bot's chats, e.g. inline,
+//so they can be found by their key when somebody opens the answer.
+//Questions are told apart by the key as questions of some sources have no ID
+type QuestionRepository struct {
+	mu        sync.RWMutex
+	questions map[int]*model.Question
+	next      int
+	oldest    int
+	logger    *logrus.Logger
+}
+
+func (r *QuestionRepository) Add(q *model.Question) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.next
+	r.next++
+	r.questions[key] = q
+
+	// Keys are given in order, so the oldest question has the least key
+	for len(r.questions) > maxSharedQuestions {
+		delete(r.questions, r.oldest)
+		r.oldest++
+	}
+
+	return key
+}
+
+func (r *QuestionRepository) Find(key int) *model.Question {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.questions[key]
+}
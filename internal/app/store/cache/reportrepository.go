@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sync"
+	"time"
+)
+
+type ReportRepository struct {
+	mu      sync.RWMutex
+	reports []*model.Report
+	logger  *logrus.Logger
+}
+
+func (r *ReportRepository) CreateReport(report *model.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report.ID = len(r.reports) + 1
+	report.CreatedAt = time.Now()
+	r.reports = append(r.reports, report)
+
+	r.logger.Infof("New report from user '%d': %s", report.UserID, report.Text)
+	return nil
+}
+
+func (r *ReportRepository) Recent(limit int) []*model.Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reports := make([]*model.Report, 0, limit)
+	for i := len(r.reports) - 1; i >= 0 && len(reports) < limit; i-- {
+		reports = append(reports, r.reports[i])
+	}
+
+	return reports
+}
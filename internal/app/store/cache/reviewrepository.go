@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+type ReviewRepository struct {
+	mu     sync.RWMutex
+	cards  map[int][]*model.ReviewCard
+	logger *logrus.Logger
+}
+
+//AddCard schedules the question for the user's review unless it is scheduled already
+func (r *ReviewRepository) AddCard(userID int, question *model.Question) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, card := range r.cards[userID] {
+		if card.Question == question || (question.ID != 0 && card.Question.ID == question.ID) {
+			return
+		}
+	}
+
+	r.cards[userID] = append(r.cards[userID], model.NewReviewCard(question))
+}
+
+//Due returns the user's cards due at the moment, the most overdue first
+func (r *ReviewRepository) Due(userID int, now time.Time) []*model.ReviewCard {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	due := make([]*model.ReviewCard, 0)
+	for _, card := range r.cards[userID] {
+		if card.IsDue(now) {
+			due = append(due, card)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Due.Before(due[j].Due)
+	})
+
+	return due
+}
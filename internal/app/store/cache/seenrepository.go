@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+//seenQuestions are IDs of questions received by a chat in the order they were received
+type seenQuestions struct {
+	ids   []int
+	index map[int]struct{}
+}
+
+type SeenRepository struct {
+	mu     sync.RWMutex
+	seen   map[int64]*seenQuestions
+	logger *logrus.Logger
+}
+
+func (r *SeenRepository) Add(chatID int64, questionID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.seen[chatID]
+	if !ok {
+		s = &seenQuestions{
+			index: make(map[int]struct{}),
+		}
+		r.seen[chatID] = s
+	}
+
+	if _, ok := s.index[questionID]; ok {
+		return
+	}
+
+	s.ids = append(s.ids, questionID)
+	s.index[questionID] = struct{}{}
+}
+
+func (r *SeenRepository) Contains(chatID int64, questionID int) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.seen[chatID]
+	if !ok {
+		return false
+	}
+
+	_, ok = s.index[questionID]
+	return ok
+}
+
+//Recent returns IDs of at most limit questions received last
+func (r *SeenRepository) Recent(chatID int64, limit int) []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.seen[chatID]
+	if !ok {
+		return nil
+	}
+
+	ids := s.ids
+	if len(ids) > limit {
+		ids = ids[len(ids)-limit:]
+	}
+
+	return append([]int(nil), ids...)
+}
+
+//Reset clears the history of the chat and returns the number of questions in it
+func (r *SeenRepository) Reset(chatID int64) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.seen[chatID]
+	if !ok {
+		return 0
+	}
+
+	delete(r.seen, chatID)
+
+	r.logger.Infof("History of '%d' reset", chatID)
+	return len(s.ids)
+}
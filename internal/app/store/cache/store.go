@@ -4,11 +4,22 @@ import (
 	"github.com/sirupsen/logrus"
 	"qask_telegram/internal/app/model"
 	"qask_telegram/internal/app/store"
+	"sync"
 )
 
 type Store struct {
-	userRepository *UserRepository
-	logger         *logrus.Logger
+	mu                   sync.Mutex
+	userRepository       *UserRepository
+	reportRepository     *ReportRepository
+	groupRepository      *GroupRepository
+	seenRepository       *SeenRepository
+	historyRepository    *HistoryRepository
+	reviewRepository     *ReviewRepository
+	questionRepository   *QuestionRepository
+	submissionRepository *SubmissionRepository
+	teamRepository       *TeamRepository
+	teamResultRepository *TeamResultRepository
+	logger               *logrus.Logger
 }
 
 func New(logger *logrus.Logger) *Store {
@@ -18,6 +29,9 @@ func New(logger *logrus.Logger) *Store {
 }
 
 func (s *Store) User() store.UserRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.userRepository != nil {
 		return s.userRepository
 	}
@@ -29,3 +43,146 @@ func (s *Store) User() store.UserRepository {
 
 	return s.userRepository
 }
+
+func (s *Store) Report() store.ReportRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reportRepository != nil {
+		return s.reportRepository
+	}
+
+	s.reportRepository = &ReportRepository{
+		logger: s.logger,
+	}
+
+	return s.reportRepository
+}
+
+func (s *Store) Group() store.GroupRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.groupRepository != nil {
+		return s.groupRepository
+	}
+
+	s.groupRepository = &GroupRepository{
+		groups: make(map[int64]*model.Group),
+		logger: s.logger,
+	}
+
+	return s.groupRepository
+}
+
+func (s *Store) Seen() store.SeenRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seenRepository != nil {
+		return s.seenRepository
+	}
+
+	s.seenRepository = &SeenRepository{
+		seen:   make(map[int64]*seenQuestions),
+		logger: s.logger,
+	}
+
+	return s.seenRepository
+}
+
+func (s *Store) History() store.HistoryRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.historyRepository != nil {
+		return s.historyRepository
+	}
+
+	s.historyRepository = &HistoryRepository{
+		history:   make(map[int][]*model.HistoryEntry),
+		favorites: make(map[int][]*model.Question),
+		logger:    s.logger,
+	}
+
+	return s.historyRepository
+}
+
+func (s *Store) Review() store.ReviewRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reviewRepository != nil {
+		return s.reviewRepository
+	}
+
+	s.reviewRepository = &ReviewRepository{
+		cards:  make(map[int][]*model.ReviewCard),
+		logger: s.logger,
+	}
+
+	return s.reviewRepository
+}
+
+func (s *Store) Question() store.QuestionRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.questionRepository != nil {
+		return s.questionRepository
+	}
+
+	s.questionRepository = &QuestionRepository{
+		questions: make(map[int]*model.Question),
+		logger:    s.logger,
+	}
+
+	return s.questionRepository
+}
+
+func (s *Store) Submission() store.SubmissionRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.submissionRepository != nil {
+		return s.submissionRepository
+	}
+
+	s.submissionRepository = &SubmissionRepository{
+		logger: s.logger,
+	}
+
+	return s.submissionRepository
+}
+
+func (s *Store) Team() store.TeamRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.teamRepository != nil {
+		return s.teamRepository
+	}
+
+	s.teamRepository = &TeamRepository{
+		teams:  make(map[int]*model.Team),
+		logger: s.logger,
+	}
+
+	return s.teamRepository
+}
+
+func (s *Store) TeamResult() store.TeamResultRepository {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.teamResultRepository != nil {
+		return s.teamResultRepository
+	}
+
+	s.teamResultRepository = &TeamResultRepository{
+		stats:  make(map[int]*model.TeamStats),
+		logger: s.logger,
+	}
+
+	return s.teamResultRepository
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sync"
+	"time"
+)
+
+type SubmissionRepository struct {
+	mu          sync.RWMutex
+	submissions []*model.Submission
+	logger      *logrus.Logger
+}
+
+func (r *SubmissionRepository) CreateSubmission(submission *model.Submission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	submission.ID = len(r.submissions) + 1
+	submission.Status = model.SubmissionPending
+	submission.CreatedAt = time.Now()
+	r.submissions = append(r.submissions, submission)
+
+	r.logger.Infof("New question submitted by user '%d'", submission.UserID)
+	return nil
+}
+
+func (r *SubmissionRepository) FindSubmission(id int) *model.Submission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id < 1 || id > len(r.submissions) {
+		return nil
+	}
+
+	return r.submissions[id-1]
+}
+
+//Pending returns submissions waiting for moderation, the oldest first
+func (r *SubmissionRepository) Pending() []*model.Submission {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pending := make([]*model.Submission, 0)
+	for _, submission := range r.submissions {
+		if submission.Status == model.SubmissionPending {
+			pending = append(pending, submission)
+		}
+	}
+
+	return pending
+}
+
+//Moderate moves the submission from one status to another, it reports false if the submission is not in the expected status.
+//It makes sure two admins can not moderate the same submission
+func (r *SubmissionRepository) Moderate(id int, adminID int, from string, to string, reason string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > len(r.submissions) || r.submissions[id-1].Status != from {
+		return false
+	}
+
+	submission := r.submissions[id-1]
+	submission.Status = to
+	submission.Reason = reason
+	submission.ReviewedBy = adminID
+	if to == model.SubmissionPending {
+		submission.ReviewedBy = 0
+	}
+
+	r.logger.Infof("Submission '%d' %s by admin '%d'", id, to, adminID)
+	return true
+}
+
+//EditField sets a field of the question of a pending submission, it reports false if the submission
+//has been moderated meanwhile, so an approved question can not be changed
+func (r *SubmissionRepository) EditField(id int, field string, text string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > len(r.submissions) || r.submissions[id-1].Status != model.SubmissionPending {
+		return false
+	}
+
+	r.submissions[id-1].Question.SetField(field, text)
+	return true
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"crypto/rand"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"qask_telegram/internal/app/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	teamCodeLength = 6
+	//teamCodeAlphabet has no characters which are easy to confuse like 0 and O
+	teamCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+type TeamRepository struct {
+	mu     sync.RWMutex
+	teams  map[int]*model.Team
+	lastID int
+	logger *logrus.Logger
+}
+
+func (r *TeamRepository) CreateTeam(name string, captainID int) *model.Team {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	team := &model.Team{
+		ID:        r.lastID,
+		Name:      name,
+		Code:      r.newCode(),
+		CaptainID: captainID,
+		Members:   []int{captainID},
+		CreatedAt: time.Now(),
+	}
+	r.teams[team.ID] = team
+
+	r.logger.Infof("New team '%d' '%s' created by user '%d'", team.ID, name, captainID)
+	return team
+}
+
+//newCode returns a random invite code which no team has, r.mu must be held
+func (r *TeamRepository) newCode() string {
+	for {
+		var code strings.Builder
+		for i := 0; i < teamCodeLength; i++ {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(teamCodeAlphabet))))
+			if err != nil {
+				panic(err)
+			}
+			code.WriteByte(teamCodeAlphabet[n.Int64()])
+		}
+
+		if r.findByCode(code.String()) == nil {
+			return code.String()
+		}
+	}
+}
+
+func (r *TeamRepository) FindTeam(id int) *model.Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.teams[id]
+}
+
+func (r *TeamRepository) FindTeamByCode(code string) *model.Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.findByCode(strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func (r *TeamRepository) findByCode(code string) *model.Team {
+	for _, team := range r.teams {
+		if team.Code == code {
+			return team
+		}
+	}
+
+	return nil
+}
+
+func (r *TeamRepository) RemoveTeam(id int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.teams, id)
+	r.logger.Infof("Team '%d' removed", id)
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"github.com/sirupsen/logrus"
+	"qask_telegram/internal/app/model"
+	"sort"
+	"sync"
+)
+
+type TeamResultRepository struct {
+	mu     sync.RWMutex
+	stats  map[int]*model.TeamStats
+	logger *logrus.Logger
+}
+
+func (r *TeamResultRepository) AddResult(result *model.TeamResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[result.TeamID]
+	if !ok {
+		stats = &model.TeamStats{
+			TeamID: result.TeamID,
+		}
+		r.stats[result.TeamID] = stats
+	}
+
+	stats.Record(result)
+}
+
+func (r *TeamResultRepository) Stats(teamID int) model.TeamStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if stats, ok := r.stats[teamID]; ok {
+		return *stats
+	}
+
+	return model.TeamStats{
+		TeamID: teamID,
+	}
+}
+
+//Top returns the stats of the teams with the most points, teams with equal points are ordered by correct answers
+func (r *TeamResultRepository) Top(limit int) []model.TeamStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	top := make([]model.TeamStats, 0, len(r.stats))
+	for _, stats := range r.stats {
+		top = append(top, *stats)
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Points != top[j].Points {
+			return top[i].Points > top[j].Points
+		}
+		return top[i].Correct > top[j].Correct
+	})
+
+	if len(top) > limit {
+		top = top[:limit]
+	}
+
+	return top
+}
@@ -3,22 +3,28 @@ package cache
 import (
 	"github.com/sirupsen/logrus"
 	"qask_telegram/internal/app/model"
+	"sync"
+	"time"
 )
 
 type UserRepository struct {
+	mu     sync.RWMutex
 	users  map[int]*model.User
 	logger *logrus.Logger
 }
 
 func (u *UserRepository) CreateUser(id int) *model.User {
+	u.mu.Lock()
+	defer u.mu.Unlock()
 
-	if user := u.FindUser(id); user != nil {
+	if user, ok := u.users[id]; ok {
 		return user
 	}
 
 	newUser := &model.User{}
 
 	newUser.UserId = id
+	newUser.CreatedAt = time.Now()
 	newUser.Registered = false
 	newUser.QuestSubscribtion = true
 	newUser.MathProblemSubscribtion = true
@@ -57,10 +63,40 @@ func (u *UserRepository) RegisterUser(chatid int, name string) error {
 */
 
 func (u *UserRepository) FindUser(chatid int) *model.User {
-	if _, ok := u.users[chatid]; ok {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	user, ok := u.users[chatid]
+	if ok {
 		u.logger.Debugf("User with chat id '%d' found", chatid)
 	} else {
 		u.logger.Debugf("User with chat id '%d' not found", chatid)
 	}
-	return u.users[chatid]
+	return user
+}
+
+func (u *UserRepository) All() []*model.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]*model.User, 0, len(u.users))
+	for _, user := range u.users {
+		users = append(users, user)
+	}
+
+	return users
+}
+
+func (u *UserRepository) Referrals(referrerID int) []*model.User {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := make([]*model.User, 0)
+	for _, user := range u.users {
+		if user.ReferrerID == referrerID {
+			users = append(users, user)
+		}
+	}
+
+	return users
 }
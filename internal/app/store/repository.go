@@ -2,10 +2,68 @@ package store
 
 import (
 	"qask_telegram/internal/app/model"
+	"time"
 )
 
 type UserRepository interface {
 	CreateUser(int) *model.User
 	RegisterUser(int, string) error
 	FindUser(int) *model.User
+	All() []*model.User
+	Referrals(int) []*model.User
+}
+
+type GroupRepository interface {
+	CreateGroup(int64) *model.Group
+	FindGroup(int64) *model.Group
+}
+
+type SeenRepository interface {
+	Add(int64, int)
+	Contains(int64, int) bool
+	Recent(int64, int) []int
+	Reset(int64) int
+}
+
+type HistoryRepository interface {
+	AddQuestion(int, *model.Question) *model.HistoryEntry
+	Recent(int, int) []*model.HistoryEntry
+	AddFavorite(int, *model.Question) bool
+	Favorites(int) []*model.Question
+}
+
+type ReviewRepository interface {
+	AddCard(int, *model.Question)
+	Due(int, time.Time) []*model.ReviewCard
+}
+
+type QuestionRepository interface {
+	Add(*model.Question) int
+	Find(int) *model.Question
+}
+
+type SubmissionRepository interface {
+	CreateSubmission(*model.Submission) error
+	FindSubmission(int) *model.Submission
+	Pending() []*model.Submission
+	Moderate(int, int, string, string, string) bool
+	EditField(int, string, string) bool
+}
+
+type TeamRepository interface {
+	CreateTeam(string, int) *model.Team
+	FindTeam(int) *model.Team
+	FindTeamByCode(string) *model.Team
+	RemoveTeam(int)
+}
+
+type TeamResultRepository interface {
+	AddResult(*model.TeamResult)
+	Stats(int) model.TeamStats
+	Top(int) []model.TeamStats
+}
+
+type ReportRepository interface {
+	CreateReport(*model.Report) error
+	Recent(int) []*model.Report
 }
@@ -2,4 +2,13 @@ package store
 
 type Store interface {
 	User() UserRepository
+	Report() ReportRepository
+	Group() GroupRepository
+	Seen() SeenRepository
+	History() HistoryRepository
+	Review() ReviewRepository
+	Question() QuestionRepository
+	Submission() SubmissionRepository
+	Team() TeamRepository
+	TeamResult() TeamResultRepository
 }